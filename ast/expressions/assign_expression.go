@@ -0,0 +1,33 @@
+package expressions
+
+import (
+	"bytes"
+	"lang/lexer/token"
+)
+
+// AssignExpression assigns Value to Target, which is an Identifier or a
+// MemberExpression, and evaluates to Value, so that a = b = c assigns c to
+// both.
+type AssignExpression struct {
+	Token  token.Token
+	Target Expression
+	Value  Expression
+}
+
+func (ae *AssignExpression) TokenValue() string {
+	return ae.Token.Value
+}
+
+func (ae *AssignExpression) expressionNode() {}
+
+func (ae *AssignExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ae.Target.String())
+	out.WriteString(" = ")
+	out.WriteString(ae.Value.String())
+	out.WriteString(")")
+
+	return out.String()
+}
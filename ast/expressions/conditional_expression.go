@@ -0,0 +1,33 @@
+package expressions
+
+import (
+	"bytes"
+	"lang/lexer/token"
+)
+
+type ConditionalExpression struct {
+	Token       token.Token
+	Condition   Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+func (ce *ConditionalExpression) TokenValue() string {
+	return ce.Token.Value
+}
+
+func (ce *ConditionalExpression) expressionNode() {}
+
+func (ce *ConditionalExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ce.Condition.String())
+	out.WriteString(" ? ")
+	out.WriteString(ce.Consequence.String())
+	out.WriteString(" : ")
+	out.WriteString(ce.Alternative.String())
+	out.WriteString(")")
+
+	return out.String()
+}
@@ -0,0 +1,78 @@
+package expressions
+
+import (
+	"bytes"
+	"lang/ast"
+	"lang/lexer/token"
+	"strings"
+)
+
+// Parameter is a function parameter, optionally with a default value or
+// marked as a rest parameter that collects the remaining arguments.
+type Parameter struct {
+	Name    *Identifier
+	Default Expression
+	Rest    bool
+}
+
+func (p *Parameter) String() string {
+	switch {
+	case p.Rest:
+		return "..." + p.Name.String()
+	case p.Default != nil:
+		return p.Name.String() + " = " + p.Default.String()
+	default:
+		return p.Name.String()
+	}
+}
+
+// FunctionLiteral is either a function(a, b) { ... } literal or an arrow
+// function. The body of an arrow function is either a block or, for concise
+// arrow functions such as x => x * 2, a single expression; the expressions
+// package cannot refer to blocks directly, so Body holds either.
+type FunctionLiteral struct {
+	Token      token.Token
+	Name       *Identifier
+	Parameters []*Parameter
+	Body       ast.Node
+	Arrow      bool
+}
+
+func (fl *FunctionLiteral) TokenValue() string {
+	return fl.Token.Value
+}
+
+func (fl *FunctionLiteral) expressionNode() {}
+
+func (fl *FunctionLiteral) String() string {
+	var out bytes.Buffer
+
+	params := make([]string, 0, len(fl.Parameters))
+	for _, p := range fl.Parameters {
+		params = append(params, p.String())
+	}
+
+	// Arrow functions are parenthesised like other compound expressions, so
+	// that (x => x)(1) does not print as a call in the body.
+	if fl.Arrow {
+		out.WriteString("(")
+	} else {
+		out.WriteString("function")
+		if fl.Name != nil {
+			out.WriteString(" " + fl.Name.String())
+		}
+	}
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(")")
+	if fl.Arrow {
+		out.WriteString(" =>")
+	}
+	out.WriteString(" ")
+	out.WriteString(fl.Body.String())
+	if fl.Arrow {
+		out.WriteString(")")
+	}
+
+	return out.String()
+}
@@ -0,0 +1,37 @@
+package expressions
+
+import (
+	"bytes"
+	"lang/lexer/token"
+)
+
+// MemberExpression is a property access such as a.b. Optional accesses,
+// written a?.b, evaluate to null instead of failing when the object is null.
+type MemberExpression struct {
+	Token    token.Token
+	Object   Expression
+	Property *Identifier
+	Optional bool
+}
+
+func (me *MemberExpression) TokenValue() string {
+	return me.Token.Value
+}
+
+func (me *MemberExpression) expressionNode() {}
+
+func (me *MemberExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(me.Object.String())
+	if me.Optional {
+		out.WriteString("?.")
+	} else {
+		out.WriteString(".")
+	}
+	out.WriteString(me.Property.String())
+	out.WriteString(")")
+
+	return out.String()
+}
@@ -1,9 +1,15 @@
 package expressions
 
-import "lang/lexer/token"
+import (
+	"lang/lexer/token"
+	"math/big"
+)
 
+// Number lists the representations a numeric literal can take. Integers that
+// overflow int64 become *big.Int, and decimal literals with an m suffix
+// (19.99m) are held exactly as *big.Rat.
 type Number interface {
-	int64 | float64
+	int64 | float64 | *big.Int | *big.Rat
 }
 
 type NumberLiteral[T Number] struct {
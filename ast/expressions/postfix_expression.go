@@ -0,0 +1,29 @@
+package expressions
+
+import (
+	"bytes"
+	"lang/lexer/token"
+)
+
+type PostfixExpression struct {
+	Token    token.Token
+	Left     Expression
+	Operator string
+}
+
+func (pe *PostfixExpression) TokenValue() string {
+	return pe.Token.Value
+}
+
+func (pe *PostfixExpression) expressionNode() {}
+
+func (pe *PostfixExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(pe.Left.String())
+	out.WriteString(pe.Operator)
+	out.WriteString(")")
+
+	return out.String()
+}
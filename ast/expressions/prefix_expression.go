@@ -3,6 +3,8 @@ package expressions
 import (
 	"bytes"
 	"lang/lexer/token"
+	"unicode"
+	"unicode/utf8"
 )
 
 type PrefixExpression struct {
@@ -22,6 +24,10 @@ func (pe *PrefixExpression) String() string {
 
 	out.WriteString("(")
 	out.WriteString(pe.Operator)
+	// Word operators such as not need a space before their operand.
+	if r, _ := utf8.DecodeLastRuneInString(pe.Operator); unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+		out.WriteString(" ")
+	}
 	out.WriteString(pe.Right.String())
 	out.WriteString(")")
 
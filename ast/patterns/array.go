@@ -0,0 +1,39 @@
+package patterns
+
+import (
+	"bytes"
+	"lang/ast/expressions"
+	"lang/lexer/token"
+	"strings"
+)
+
+// Array destructures by index, as in [first, second, ...rest].
+type Array struct {
+	Token    token.Token
+	Elements []Pattern
+	Rest     *expressions.Identifier
+}
+
+func (a *Array) TokenValue() string {
+	return a.Token.Value
+}
+
+func (a *Array) patternNode() {}
+
+func (a *Array) String() string {
+	var out bytes.Buffer
+
+	elements := make([]string, 0, len(a.Elements)+1)
+	for _, e := range a.Elements {
+		elements = append(elements, e.String())
+	}
+	if a.Rest != nil {
+		elements = append(elements, "..."+a.Rest.String())
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+
+	return out.String()
+}
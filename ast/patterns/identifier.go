@@ -0,0 +1,25 @@
+package patterns
+
+import (
+	"lang/ast/expressions"
+)
+
+// Identifier binds a single name, optionally with a default used when the
+// destructured value is missing.
+type Identifier struct {
+	Name    *expressions.Identifier
+	Default expressions.Expression
+}
+
+func (i *Identifier) TokenValue() string {
+	return i.Name.TokenValue()
+}
+
+func (i *Identifier) patternNode() {}
+
+func (i *Identifier) String() string {
+	if i.Default != nil {
+		return i.Name.String() + " = " + i.Default.String()
+	}
+	return i.Name.String()
+}
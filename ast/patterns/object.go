@@ -0,0 +1,53 @@
+package patterns
+
+import (
+	"bytes"
+	"lang/ast/expressions"
+	"lang/lexer/token"
+	"strings"
+)
+
+// Property destructures the field Key into Value. In the shorthand { x },
+// Value is an Identifier pattern with the same name as Key.
+type Property struct {
+	Key   *expressions.Identifier
+	Value Pattern
+}
+
+func (p *Property) String() string {
+	if id, ok := p.Value.(*Identifier); ok && id.Name.Value == p.Key.Value {
+		return id.String()
+	}
+	return p.Key.String() + ": " + p.Value.String()
+}
+
+// Object destructures by field name, as in { x, y: alias, ...rest }.
+type Object struct {
+	Token      token.Token
+	Properties []*Property
+	Rest       *expressions.Identifier
+}
+
+func (o *Object) TokenValue() string {
+	return o.Token.Value
+}
+
+func (o *Object) patternNode() {}
+
+func (o *Object) String() string {
+	var out bytes.Buffer
+
+	properties := make([]string, 0, len(o.Properties)+1)
+	for _, p := range o.Properties {
+		properties = append(properties, p.String())
+	}
+	if o.Rest != nil {
+		properties = append(properties, "..."+o.Rest.String())
+	}
+
+	out.WriteString("{ ")
+	out.WriteString(strings.Join(properties, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
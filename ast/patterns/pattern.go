@@ -0,0 +1,10 @@
+package patterns
+
+import "lang/ast"
+
+// Pattern is the target of a destructuring binding, such as the [a, b] in
+// let [a, b] = pair;
+type Pattern interface {
+	ast.Node
+	patternNode()
+}
@@ -3,13 +3,17 @@ package statements
 import (
 	"bytes"
 	"lang/ast/expressions"
+	"lang/ast/patterns"
 	"lang/lexer/token"
 )
 
+// Assign is a let declaration binding Value to Pattern, which is a
+// patterns.Identifier for a single name, as in let x = 1;, or a destructuring
+// pattern, as in let [a, b] = pair;
 type Assign struct {
-	Token token.Token
-	Name  *expressions.Identifier
-	Value expressions.Expression
+	Token   token.Token
+	Pattern patterns.Pattern
+	Value   expressions.Expression
 }
 
 func (a *Assign) TokenValue() string {
@@ -22,7 +26,7 @@ func (a *Assign) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(a.TokenValue() + " ")
-	out.WriteString(a.Name.String())
+	out.WriteString(a.Pattern.String())
 	out.WriteString(" = ")
 
 	if a.Value != nil {
@@ -0,0 +1,30 @@
+package statements
+
+import (
+	"bytes"
+	"lang/lexer/token"
+)
+
+type Block struct {
+	Token      token.Token
+	Statements []Statement
+}
+
+func (b *Block) TokenValue() string {
+	return b.Token.Value
+}
+
+func (b *Block) statementNode() {}
+
+func (b *Block) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("{ ")
+	for _, s := range b.Statements {
+		out.WriteString(s.String())
+		out.WriteString(" ")
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
@@ -0,0 +1,21 @@
+package statements
+
+import "lang/lexer/token"
+
+// ErrorNode stands in for a statement that could not be parsed, so that the
+// rest of the tree remains usable. Span covers the source skipped while
+// recovering.
+type ErrorNode struct {
+	Token token.Token
+	Span  token.Span
+}
+
+func (e *ErrorNode) TokenValue() string {
+	return e.Token.Value
+}
+
+func (e *ErrorNode) statementNode() {}
+
+func (e *ErrorNode) String() string {
+	return "<error>"
+}
@@ -0,0 +1,36 @@
+package statements
+
+import (
+	"bytes"
+	"lang/ast/expressions"
+	"lang/ast/patterns"
+	"lang/lexer/token"
+)
+
+// ForIn loops over the elements of Iterable, binding each to Binding, as in
+// for [k, v] in pairs { ... }.
+type ForIn struct {
+	Token    token.Token
+	Binding  patterns.Pattern
+	Iterable expressions.Expression
+	Body     *Block
+}
+
+func (f *ForIn) TokenValue() string {
+	return f.Token.Value
+}
+
+func (f *ForIn) statementNode() {}
+
+func (f *ForIn) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(f.TokenValue() + " ")
+	out.WriteString(f.Binding.String())
+	out.WriteString(" in ")
+	out.WriteString(f.Iterable.String())
+	out.WriteString(" ")
+	out.WriteString(f.Body.String())
+
+	return out.String()
+}
@@ -0,0 +1,157 @@
+// Package diag defines the diagnostics reported by the lexer, the parser and
+// later passes over the source.
+package diag
+
+import (
+	"fmt"
+	"lang/lexer/token"
+	"slices"
+	"strings"
+)
+
+// Severity classifies how serious a diagnostic is.
+type Severity int
+
+const (
+	// Error is reported for input that cannot be processed.
+	Error Severity = iota
+	// Warning is reported for input that is valid but probably a mistake.
+	Warning
+	// Note is reported for information that accompanies other diagnostics.
+	Note
+)
+
+var severityToString = map[Severity]string{
+	Error:   "error",
+	Warning: "warning",
+	Note:    "note",
+}
+
+func (s Severity) String() string {
+	name, exists := severityToString[s]
+
+	if exists {
+		return name
+	}
+
+	return "unknown"
+}
+
+// Label attaches a message to a secondary span of source, such as the opening
+// brace of an unclosed block.
+type Label struct {
+	Span    token.Span
+	Message string
+}
+
+// Fix is a suggested edit that replaces the source in Span with Replacement.
+// An empty span inserts Replacement at its start.
+type Fix struct {
+	Message     string
+	Span        token.Span
+	Replacement string
+}
+
+// Diagnostic describes a problem found in the source.
+type Diagnostic struct {
+	Severity Severity
+	// Code identifies the kind of problem. Codes are stable across releases, so
+	// tools may filter on them.
+	Code    string
+	Message string
+	// Span is the primary location of the problem.
+	Span   token.Span
+	Labels []Label
+	Notes  []string
+	Fixes  []Fix
+}
+
+// Errorf creates an error diagnostic with a formatted message.
+func Errorf(code string, span token.Span, format string, args ...any) *Diagnostic {
+	return &Diagnostic{Severity: Error, Code: code, Span: span, Message: fmt.Sprintf(format, args...)}
+}
+
+// Warningf creates a warning diagnostic with a formatted message.
+func Warningf(code string, span token.Span, format string, args ...any) *Diagnostic {
+	return &Diagnostic{Severity: Warning, Code: code, Span: span, Message: fmt.Sprintf(format, args...)}
+}
+
+// WithLabel adds a labelled secondary span and returns d.
+func (d *Diagnostic) WithLabel(span token.Span, message string) *Diagnostic {
+	d.Labels = append(d.Labels, Label{Span: span, Message: message})
+	return d
+}
+
+// WithNote adds a note and returns d.
+func (d *Diagnostic) WithNote(note string) *Diagnostic {
+	d.Notes = append(d.Notes, note)
+	return d
+}
+
+// WithFix adds a suggested fix and returns d.
+func (d *Diagnostic) WithFix(message string, span token.Span, replacement string) *Diagnostic {
+	d.Fixes = append(d.Fixes, Fix{Message: message, Span: span, Replacement: replacement})
+	return d
+}
+
+// Error formats the diagnostic on one line, as in
+// 3:7: error[P0001]: expected next token to be Assign, got Number instead.
+func (d *Diagnostic) Error() string {
+	return fmt.Sprintf("%s: %s[%s]: %s", d.Span.Start, d.Severity, d.Code, d.Message)
+}
+
+// List is a list of diagnostics.
+type List []*Diagnostic
+
+// Count returns the number of diagnostics with the given severity.
+func (l List) Count(severity Severity) int {
+	n := 0
+	for _, d := range l {
+		if d.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// HasErrors reports whether any diagnostic is an error.
+func (l List) HasErrors() bool {
+	return l.Count(Error) > 0
+}
+
+// WithCode returns the diagnostics with the given code.
+func (l List) WithCode(code string) List {
+	var matching List
+	for _, d := range l {
+		if d.Code == code {
+			matching = append(matching, d)
+		}
+	}
+	return matching
+}
+
+// Error formats the diagnostics one per line, so that a List can be returned
+// as an error.
+func (l List) Error() string {
+	lines := make([]string, len(l))
+	for i, d := range l {
+		lines[i] = d.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Err returns l as an error if it contains any errors, or nil otherwise.
+func (l List) Err() error {
+	if !l.HasErrors() {
+		return nil
+	}
+	return l
+}
+
+// Sort orders the diagnostics by the start of their primary span, keeping the
+// order in which diagnostics at the same position were reported.
+func (l List) Sort() {
+	slices.SortStableFunc(l, func(a, b *Diagnostic) int {
+		return a.Span.Start.Offset - b.Span.Start.Offset
+	})
+}
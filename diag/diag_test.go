@@ -0,0 +1,114 @@
+package diag
+
+import (
+	"errors"
+	"lang/lexer/token"
+	"reflect"
+	"testing"
+)
+
+func span(start, end int) token.Span {
+	return token.Span{
+		Start: token.Pos{Offset: start, Line: 1, Column: start + 1},
+		End:   token.Pos{Offset: end, Line: 1, Column: end + 1},
+	}
+}
+
+func TestDiagnostic_Error(t *testing.T) {
+	tests := []struct {
+		name       string
+		diagnostic *Diagnostic
+		expected   string
+	}{
+		{
+			name:       "Error",
+			diagnostic: Errorf("P0001", span(6, 7), "expected next token to be %s", "Assign"),
+			expected:   "1:7: error[P0001]: expected next token to be Assign",
+		},
+		{
+			name:       "Warning",
+			diagnostic: Warningf("L0005", span(0, 3), "confusable"),
+			expected:   "1:1: warning[L0005]: confusable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := tt.diagnostic.Error(); actual != tt.expected {
+				t.Errorf("Error() = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDiagnostic_Builders(t *testing.T) {
+	d := Errorf("P0009", span(9, 9), "unclosed").
+		WithLabel(span(0, 1), "opened here").
+		WithNote("blocks must be closed").
+		WithFix("close the block", span(9, 9), "}")
+
+	expected := &Diagnostic{
+		Severity: Error,
+		Code:     "P0009",
+		Message:  "unclosed",
+		Span:     span(9, 9),
+		Labels:   []Label{{Span: span(0, 1), Message: "opened here"}},
+		Notes:    []string{"blocks must be closed"},
+		Fixes:    []Fix{{Message: "close the block", Span: span(9, 9), Replacement: "}"}},
+	}
+
+	if !reflect.DeepEqual(d, expected) {
+		t.Errorf("got %+v, want %+v", d, expected)
+	}
+}
+
+func TestList(t *testing.T) {
+	first := Errorf("P0001", span(0, 1), "first")
+	second := Warningf("L0005", span(4, 5), "second")
+	third := Errorf("P0002", span(4, 5), "third")
+	l := List{third, second, first}
+
+	l.Sort()
+	if expected := (List{first, third, second}); !reflect.DeepEqual(l, expected) {
+		t.Errorf("Sort() = %v, want %v", l, expected)
+	}
+
+	if n := l.Count(Error); n != 2 {
+		t.Errorf("Count(Error) = %d, want 2", n)
+	}
+	if !l.HasErrors() {
+		t.Errorf("HasErrors() = false, want true")
+	}
+	if (List{second}).HasErrors() {
+		t.Errorf("HasErrors() = true for warnings only")
+	}
+	if matching := l.WithCode("P0002"); !reflect.DeepEqual(matching, List{third}) {
+		t.Errorf("WithCode() = %v, want %v", matching, List{third})
+	}
+}
+
+func TestList_Err(t *testing.T) {
+	warning := Warningf("L0005", span(0, 3), "confusable")
+	if err := (List{warning}).Err(); err != nil {
+		t.Errorf("Err() = %v for warnings only, want nil", err)
+	}
+	if err := (List{}).Err(); err != nil {
+		t.Errorf("Err() = %v for no diagnostics, want nil", err)
+	}
+
+	l := List{warning, Errorf("P0001", span(4, 5), "unexpected")}
+	err := l.Err()
+	if err == nil {
+		t.Fatalf("Err() = nil, want an error")
+	}
+
+	expected := "1:1: warning[L0005]: confusable\n1:5: error[P0001]: unexpected"
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, want %q", err.Error(), expected)
+	}
+
+	var list List
+	if !errors.As(err, &list) || len(list) != 2 {
+		t.Errorf("errors.As did not recover the list from %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+package diag
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiBlue   = "\x1b[34m"
+	ansiCyan   = "\x1b[36m"
+)
+
+var severityToColor = map[Severity]string{
+	Error:   ansiRed,
+	Warning: ansiYellow,
+	Note:    ansiCyan,
+}
+
+// Renderer prints diagnostics in the style of rustc, showing each line of
+// source involved with the primary span underlined by carets and secondary
+// spans by dashes:
+//
+//	error[P0005]: ambiguous line break
+//	 --> 2:1
+//	  |
+//	1 | let f = g
+//	  |         - the previous statement ends here
+//	2 | (a)
+//	  | ^
+//	  |
+//	  = help: end the previous statement: insert ";" at 1:10
+type Renderer struct {
+	source string
+	color  bool
+}
+
+// RenderOption configures a Renderer.
+type RenderOption func(*Renderer)
+
+// WithColor enables or disables ANSI colour in the output.
+func WithColor(enabled bool) RenderOption {
+	return func(r *Renderer) {
+		r.color = enabled
+	}
+}
+
+// NewRenderer creates a renderer for diagnostics reported against source.
+func NewRenderer(source string, opts ...RenderOption) *Renderer {
+	r := &Renderer{source: source}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// annotation is an underline beneath part of a line of source.
+type annotation struct {
+	start, end int
+	primary    bool
+	message    string
+}
+
+// Render writes d to w.
+func (r *Renderer) Render(w io.Writer, d *Diagnostic) error {
+	var out strings.Builder
+
+	sevColor := severityToColor[d.Severity]
+	out.WriteString(r.paint(ansiBold+sevColor, d.Severity.String()+"["+d.Code+"]"))
+	out.WriteString(r.paint(ansiBold, ": "+d.Message))
+	out.WriteString("\n")
+
+	lines := map[int][]annotation{}
+	r.annotate(lines, d.Span.Start.Offset, d.Span.End.Offset, true, "")
+	for _, l := range d.Labels {
+		r.annotate(lines, l.Span.Start.Offset, l.Span.End.Offset, false, l.Message)
+	}
+
+	starts := make([]int, 0, len(lines))
+	for start := range lines {
+		starts = append(starts, start)
+	}
+	slices.Sort(starts)
+
+	lineNumbers := make(map[int]int, len(starts))
+	width := 1
+	for _, start := range starts {
+		n := strings.Count(r.source[:start], "\n") + 1
+		lineNumbers[start] = n
+		width = max(width, len(strconv.Itoa(n)))
+	}
+	gutter := strings.Repeat(" ", width)
+
+	out.WriteString(fmt.Sprintf("%s%s %s\n", gutter, r.paint(ansiBlue, "-->"), d.Span.Start))
+	out.WriteString(r.paint(ansiBlue, gutter+" |") + "\n")
+
+	for _, start := range starts {
+		text := r.lineAt(start)
+		number := fmt.Sprintf("%*d", width, lineNumbers[start])
+		out.WriteString(r.paint(ansiBlue, number+" |") + " " + text + "\n")
+
+		for _, a := range lines[start] {
+			out.WriteString(r.paint(ansiBlue, gutter+" |") + " ")
+			out.WriteString(indentFor(text[:a.start]))
+
+			mark, color := "-", ansiBlue
+			if a.primary {
+				mark, color = "^", sevColor
+			}
+			underline := strings.Repeat(mark, max(1, len([]rune(text[a.start:a.end]))))
+			if a.message != "" {
+				underline += " " + a.message
+			}
+			out.WriteString(r.paint(ansiBold+color, underline) + "\n")
+		}
+	}
+
+	if len(d.Notes) > 0 || len(d.Fixes) > 0 {
+		out.WriteString(r.paint(ansiBlue, gutter+" |") + "\n")
+	}
+	for _, note := range d.Notes {
+		out.WriteString(fmt.Sprintf("%s %s %s\n", gutter, r.paint(ansiBlue, "="), r.paint(ansiBold, "note")+": "+note))
+	}
+	for _, fix := range d.Fixes {
+		out.WriteString(fmt.Sprintf("%s %s %s\n", gutter, r.paint(ansiBlue, "="), r.paint(ansiBold, "help")+": "+describeFix(fix)))
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// RenderAll writes each diagnostic in l to w, separated by blank lines.
+func (r *Renderer) RenderAll(w io.Writer, l List) error {
+	for i, d := range l {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if err := r.Render(w, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// annotate records an underline from the byte offset start to end, keyed by
+// the offset at which its line begins. Spans covering several lines are
+// underlined to the end of their first line, and spans starting on a line
+// ending are clamped to the end of the line's text.
+func (r *Renderer) annotate(lines map[int][]annotation, start, end int, primary bool, message string) {
+	start = min(max(start, 0), len(r.source))
+	end = min(max(end, start), len(r.source))
+
+	lineStart := strings.LastIndexByte(r.source[:start], '\n') + 1
+	lineEnd := lineStart + len(r.lineAt(lineStart))
+
+	lines[lineStart] = append(lines[lineStart], annotation{
+		start:   min(start, lineEnd) - lineStart,
+		end:     min(end, lineEnd) - lineStart,
+		primary: primary,
+		message: message,
+	})
+}
+
+// lineAt returns the line of source beginning at the byte offset start,
+// without its line ending.
+func (r *Renderer) lineAt(start int) string {
+	line := r.source[start:]
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	return strings.TrimSuffix(line, "\r")
+}
+
+func (r *Renderer) paint(color, s string) string {
+	if !r.color {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+// indentFor returns blank space as wide as prefix, keeping its tabs so that
+// underlines line up however the terminal expands them.
+func indentFor(prefix string) string {
+	var b strings.Builder
+	for _, ch := range prefix {
+		if ch == '\t' {
+			b.WriteRune('\t')
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return b.String()
+}
+
+func describeFix(fix Fix) string {
+	if fix.Span.Start.Offset == fix.Span.End.Offset {
+		return fmt.Sprintf("%s: insert %q at %s", fix.Message, fix.Replacement, fix.Span.Start)
+	}
+	return fmt.Sprintf("%s: replace %s with %q", fix.Message, fix.Span, fix.Replacement)
+}
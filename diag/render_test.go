@@ -0,0 +1,159 @@
+package diag
+
+import (
+	"lang/lexer/token"
+	"strings"
+	"testing"
+)
+
+// at returns a span over source from the byte offset start to end, with the
+// position of each end computed as the lexer would.
+func at(source string, start, end int) token.Span {
+	pos := func(offset int) token.Pos {
+		before := source[:offset]
+		lineStart := strings.LastIndexByte(before, '\n') + 1
+		return token.Pos{
+			Offset: offset,
+			Line:   strings.Count(before, "\n") + 1,
+			Column: len([]rune(before[lineStart:])) + 1,
+		}
+	}
+	return token.Span{Start: pos(start), End: pos(end)}
+}
+
+func TestRenderer_Render(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		diagnostic func(source string) *Diagnostic
+		expected   string
+	}{
+		{
+			name:   "Primary span",
+			source: "let x 5;",
+			diagnostic: func(src string) *Diagnostic {
+				return Errorf("P0001", at(src, 6, 7), "expected next token to be Assign, got Number instead")
+			},
+			expected: `error[P0001]: expected next token to be Assign, got Number instead
+ --> 1:7
+  |
+1 | let x 5;
+  |       ^
+`,
+		},
+		{
+			name:   "Secondary label on another line with a fix",
+			source: "let f = g\n(a)",
+			diagnostic: func(src string) *Diagnostic {
+				return Errorf("P0005", at(src, 10, 11), "ambiguous line break").
+					WithLabel(at(src, 8, 9), "the previous statement ends here").
+					WithFix("end the previous statement", at(src, 9, 9), ";")
+			},
+			expected: `error[P0005]: ambiguous line break
+ --> 2:1
+  |
+1 | let f = g
+  |         - the previous statement ends here
+2 | (a)
+  | ^
+  |
+  = help: end the previous statement: insert ";" at 1:10
+`,
+		},
+		{
+			name:   "Multi-character span after a tab with a note",
+			source: "\tpаss + 1",
+			diagnostic: func(src string) *Diagnostic {
+				return Warningf("L0005", at(src, 1, 6), "mixed scripts").WithNote("а is Cyrillic")
+			},
+			expected: "warning[L0005]: mixed scripts\n --> 1:2\n  |\n1 | \tpаss + 1\n  | \t^^^^\n  |\n  = note: а is Cyrillic\n",
+		},
+		{
+			name:   "Span at the end of input",
+			source: "function() {\n",
+			diagnostic: func(src string) *Diagnostic {
+				return Errorf("P0009", at(src, 13, 13), "unclosed block").WithLabel(at(src, 11, 12), "block opened here")
+			},
+			expected: `error[P0009]: unclosed block
+ --> 2:1
+  |
+1 | function() {
+  |            - block opened here
+2 | 
+  | ^
+`,
+		},
+		{
+			name:   "Span on a CRLF line ending",
+			source: "ab\r\ncd",
+			diagnostic: func(src string) *Diagnostic {
+				return Errorf("P0004", at(src, 3, 4), "expected ;")
+			},
+			expected: `error[P0004]: expected ;
+ --> 1:4
+  |
+1 | ab
+  |   ^
+`,
+		},
+		{
+			name:   "Wide gutter",
+			source: strings.Repeat("\n", 9) + "x y",
+			diagnostic: func(src string) *Diagnostic {
+				return Errorf("P0004", at(src, 11, 12), "expected ;")
+			},
+			expected: `error[P0004]: expected ;
+  --> 10:3
+   |
+10 | x y
+   |   ^
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			if err := NewRenderer(tt.source).Render(&out, tt.diagnostic(tt.source)); err != nil {
+				t.Fatalf("Render() returned %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("Render() =\n%s\nwant\n%s", out.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderer_Color(t *testing.T) {
+	source := "a b"
+	d := Errorf("P0004", at(source, 2, 3), "expected ;")
+
+	var out strings.Builder
+	if err := NewRenderer(source, WithColor(true)).Render(&out, d); err != nil {
+		t.Fatalf("Render() returned %v", err)
+	}
+
+	if !strings.HasPrefix(out.String(), ansiBold+ansiRed+"error[P0004]"+ansiReset) {
+		t.Errorf("expected a bold red severity, got %q", out.String())
+	}
+	if !strings.Contains(out.String(), ansiBold+ansiRed+"^"+ansiReset) {
+		t.Errorf("expected a bold red caret, got %q", out.String())
+	}
+}
+
+func TestRenderer_RenderAll(t *testing.T) {
+	source := "a b c"
+	l := List{
+		Errorf("P0004", at(source, 2, 3), "first"),
+		Errorf("P0004", at(source, 4, 5), "second"),
+	}
+
+	var out strings.Builder
+	if err := NewRenderer(source).RenderAll(&out, l); err != nil {
+		t.Fatalf("RenderAll() returned %v", err)
+	}
+
+	if n := strings.Count(out.String(), "\n\nerror["); n != 1 {
+		t.Errorf("expected diagnostics separated by one blank line, got %q", out.String())
+	}
+}
@@ -0,0 +1,57 @@
+package diag
+
+// Distance returns the optimal string alignment distance between a and b: the
+// number of rune insertions, deletions, substitutions and transpositions of
+// adjacent runes needed to turn one into the other, so that both retrun and
+// retun are one edit from return.
+func Distance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	// d[i][j] is the distance between the first i runes of s and the first j
+	// runes of t.
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(s)][len(t)]
+}
+
+// ClosestMatch returns the candidate nearest to word if it is close enough to
+// be a plausible misspelling: within one edit for every three runes of word,
+// and at least one. Ties go to the earliest candidate, and word itself is
+// never suggested. Single runes match nothing, since almost every short word
+// is one edit away from them.
+func ClosestMatch(word string, candidates []string) (string, bool) {
+	n := len([]rune(word))
+	if n < 2 {
+		return "", false
+	}
+	limit := max(1, n/3)
+
+	best, bestDistance := "", limit+1
+	for _, c := range candidates {
+		if d := Distance(word, c); d > 0 && d < bestDistance {
+			best, bestDistance = c, d
+		}
+	}
+
+	return best, best != ""
+}
@@ -0,0 +1,57 @@
+package diag
+
+import "testing"
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"return", "return", 0},
+		{"", "let", 3},
+		{"retrun", "return", 1},
+		{"fucntion", "function", 1},
+		{"retun", "return", 1},
+		{"returnn", "return", 1},
+		{"ratorn", "return", 2},
+		{"größe", "grösse", 2},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		if actual := Distance(tt.a, tt.b); actual != tt.expected {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, actual, tt.expected)
+		}
+		if actual := Distance(tt.b, tt.a); actual != tt.expected {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.b, tt.a, actual, tt.expected)
+		}
+	}
+}
+
+func TestClosestMatch(t *testing.T) {
+	candidates := []string{"if", "in", "let", "return", "function", "while"}
+
+	tests := []struct {
+		word     string
+		expected string
+		found    bool
+	}{
+		{"retrun", "return", true},
+		{"fucntion", "function", true},
+		{"ni", "in", true},
+		{"fi", "if", true},
+		{"lte", "let", true},
+		{"return", "", false},
+		{"banana", "", false},
+		{"x", "", false},
+		{"i", "", false},
+	}
+
+	for _, tt := range tests {
+		actual, found := ClosestMatch(tt.word, candidates)
+		if actual != tt.expected || found != tt.found {
+			t.Errorf("ClosestMatch(%q) = %q, %t, want %q, %t", tt.word, actual, found, tt.expected, tt.found)
+		}
+	}
+}
@@ -1,3 +1,5 @@
 module lang
 
-go 1.21
+go 1.23
+
+require golang.org/x/text v0.14.0
@@ -0,0 +1,97 @@
+package lexer
+
+import (
+	"fmt"
+	"lang/diag"
+	"lang/lexer/token"
+)
+
+// ErrorKind classifies the problems the lexer can report.
+type ErrorKind int
+
+const (
+	// UnterminatedString is reported when the input ends inside a string.
+	UnterminatedString ErrorKind = iota
+	// InvalidCharacter is reported for runes that cannot start a token.
+	InvalidCharacter
+	// MalformedNumber is reported for numeric literals such as 0x or 1__0.
+	MalformedNumber
+	// IOFailure is reported when the underlying reader returns an error other
+	// than io.EOF. Lexing stops as though the input had ended.
+	IOFailure
+)
+
+// Diagnostic codes reported by the lexer.
+const (
+	CodeUnterminatedString   = "L0001"
+	CodeInvalidCharacter     = "L0002"
+	CodeMalformedNumber      = "L0003"
+	CodeIOFailure            = "L0004"
+	CodeConfusableIdentifier = "L0005"
+)
+
+var errorKindToCode = map[ErrorKind]string{
+	UnterminatedString: CodeUnterminatedString,
+	InvalidCharacter:   CodeInvalidCharacter,
+	MalformedNumber:    CodeMalformedNumber,
+	IOFailure:          CodeIOFailure,
+}
+
+var errorKindToString = map[ErrorKind]string{
+	UnterminatedString: "unterminated string",
+	InvalidCharacter:   "invalid character",
+	MalformedNumber:    "malformed number",
+	IOFailure:          "I/O failure",
+}
+
+func (k ErrorKind) String() string {
+	name, exists := errorKindToString[k]
+
+	if exists {
+		return name
+	}
+
+	return "unknown error"
+}
+
+// Code returns the stable diagnostic code for errors of kind k.
+func (k ErrorKind) Code() string {
+	return errorKindToCode[k]
+}
+
+// Error describes a problem found while lexing, spanning the offending input
+// like a token does.
+type Error struct {
+	Kind ErrorKind
+	// Msg is a human-readable description of the problem.
+	Msg string
+	// Start is the position of the offending input, including the file name
+	// if the input has one, and End the position just past it.
+	Start token.Pos
+	End   token.Pos
+	// Err is the underlying error for IOFailure errors.
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Start, e.Msg)
+}
+
+// Span returns the range of the offending input.
+func (e *Error) Span() token.Span {
+	return token.Span{Start: e.Start, End: e.End}
+}
+
+// Unwrap returns the underlying I/O error, if any.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Diagnostic converts the error to a diagnostic spanning the offending input.
+func (e *Error) Diagnostic() *diag.Diagnostic {
+	span := e.Span()
+	if span.End.Offset < span.Start.Offset {
+		span.End = span.Start
+	}
+	return diag.Errorf(e.Kind.Code(), span, "%s", e.Msg)
+}
@@ -2,256 +2,287 @@
 package lexer
 
 import (
-	"bufio"
 	"errors"
+	"fmt"
 	"io"
+	"iter"
+	"lang/diag"
 	"lang/lexer/token"
+	"slices"
 	"strings"
 	"unicode"
 	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // Lexer is a lexer for the programming language.
 type Lexer struct {
-	// reader is the buffer containing the input string.
-	reader *bufio.Reader
+	// reader is the source of the input. It is nil when lexing in-memory
+	// input created with NewFromBytes.
+	reader io.Reader
+	// buf holds the input read from reader that may still be needed: the
+	// current token, any marked positions and the lookahead.
+	buf []byte
+	// bufOffset is the byte offset of buf[0] within the input.
+	bufOffset int
+	// readErr is the error returned by reader once it is exhausted; io.EOF
+	// when the input ended normally.
+	readErr error
+	// input is the whole source when lexing in-memory input. Token values are
+	// substrings of it.
+	input string
 	// ch is the current character being read.
 	ch rune
-	// line is the current line number.
+	// line is the current line number, starting at 1.
 	line int
-	// col is the current column number.
+	// col is the current column number, starting at 1.
 	col int
+	// columnUnit is what col counts.
+	columnUnit ColumnUnit
+	// tabWidth is the width tabs are expanded to, or zero to not expand them.
+	tabWidth int
+	// trivia enables attaching whitespace and comments to tokens.
+	trivia bool
+	// dialect selects which keywords are reserved.
+	dialect *token.Dialect
+	// offset is the byte offset of the current character.
+	offset int
+	// size is the width in bytes of the current character.
+	size int
+	// start is the position at which the current token begins.
+	start token.Pos
+	// failed is set once a reader error other than io.EOF has been reported.
+	failed bool
+	// marks holds the offsets of the marks that have not been released.
+	marks []int
+	// errors holds the problems found in the input so far.
+	errors []*Error
+	// warnings holds diagnostics about suspicious but valid input.
+	warnings diag.List
+	// file is recorded in every position.
+	file *token.File
+	// operators holds the punctuation the lexer recognises. It is shared with
+	// the package-level table until DefineOperator first changes it.
+	operators     map[rune][]operator
+	ownsOperators bool
 }
 
-// New creates a new lexer from the given reader.
-func New(r io.Reader) *Lexer {
+// New creates a new lexer from the given reader. Lines and columns are
+// numbered from 1; by default columns count runes and tabs are not expanded.
+func New(r io.Reader, opts ...Option) *Lexer {
 	l := &Lexer{
-		reader: bufio.NewReader(r),
-		line:   0,
-		col:    0,
+		reader:    r,
+		line:      1,
+		col:       1,
+		dialect:   token.Full,
+		errors:    []*Error{},
+		warnings:  diag.List{},
+		operators: operators,
+	}
+	for _, opt := range opts {
+		opt(l)
 	}
 	// Read the first character to initialize the lexer.
 	l.readNextChar()
 	return l
 }
 
-// readNextChar reads the next character from the input string.
-func (l *Lexer) readNextChar() {
-	var err error
-	l.ch, _, err = l.reader.ReadRune()
-	l.col++
-	if err == nil {
-		return
+// NewFromBytes creates a new lexer over in-memory input. The input is
+// converted to a string once and decoded in place, and token values are
+// substrings of it, so lexing does not allocate per token.
+func NewFromBytes(src []byte, opts ...Option) *Lexer {
+	l := &Lexer{
+		input:     string(src),
+		line:      1,
+		col:       1,
+		dialect:   token.Full,
+		errors:    []*Error{},
+		warnings:  diag.List{},
+		operators: operators,
 	}
-
-	if err == io.EOF {
-		l.ch = 0
-	} else {
-		panic(err)
+	for _, opt := range opts {
+		opt(l)
 	}
+	l.readNextChar()
+	return l
 }
 
-// peekNextChar returns the next character from the input string without consuming it.
-func (l *Lexer) peekNextChar() (rune, error) {
-	r, _, err := l.reader.ReadRune()
-	if err != nil {
-		if err == io.EOF {
-			return 0, nil
-		}
-		return 0, err
+// Errors returns the problems found in the input so far. Every Illegal token
+// has a corresponding error, and a failing reader is reported as an IOFailure
+// rather than a panic.
+func (l *Lexer) Errors() []*Error {
+	return l.errors
+}
+
+// Dialect returns the dialect the lexer reserves keywords for.
+func (l *Lexer) Dialect() *token.Dialect {
+	return l.dialect
+}
+
+// Warnings returns messages about suspicious but valid input, such as
+// identifiers that mix scripts and may therefore be confusable. Diagnostics
+// returns the same warnings with their positions.
+func (l *Lexer) Warnings() []string {
+	warnings := make([]string, len(l.warnings))
+	for i, w := range l.warnings {
+		warnings[i] = w.Message
 	}
+	return warnings
+}
 
-	// 'Unread' the rune so that it can be read again.
-	if err := l.reader.UnreadRune(); err != nil {
-		return 0, err
+// Diagnostics returns the errors and warnings reported so far, ordered by
+// position.
+func (l *Lexer) Diagnostics() diag.List {
+	diagnostics := make(diag.List, 0, len(l.errors)+len(l.warnings))
+	for _, err := range l.errors {
+		diagnostics = append(diagnostics, err.Diagnostic())
 	}
+	diagnostics = append(diagnostics, l.warnings...)
+	diagnostics.Sort()
+	return diagnostics
+}
+
+// addError records a problem spanning from the start of the current token to
+// the current character.
+func (l *Lexer) addError(kind ErrorKind, msg string) {
+	l.errors = append(l.errors, &Error{
+		Kind:  kind,
+		Msg:   msg,
+		Start: l.start,
+		End:   l.pos(),
+	})
+}
 
-	return r, nil
+// addIOError records a reader failure. Reads behave as though the input had
+// ended at the point of failure.
+func (l *Lexer) addIOError(err error) {
+	l.failed = true
+	l.errors = append(l.errors, &Error{
+		Kind:  IOFailure,
+		Msg:   "reading input: " + err.Error(),
+		Start: l.pos(),
+		End:   l.pos(),
+		Err:   err,
+	})
+}
+
+func (l *Lexer) addWarning(code string, msg string) {
+	l.warnings = append(l.warnings, diag.Warningf(code, token.Span{Start: l.start, End: l.pos()}, "%s", msg))
 }
 
 // Tokenize reads the entire input string and returns a slice of tokens.
 func (l *Lexer) Tokenize() []token.Token {
-	var tokens []token.Token
-	for {
-		t := l.NextToken()
-		tokens = append(tokens, t)
-		if t.Type == token.Eof {
-			break
+	return slices.Collect(l.Tokens())
+}
+
+// Tokens returns an iterator over the remaining tokens, ending with the Eof
+// token. Tokens are read from the input only as they are requested, so large
+// or continuous inputs can be processed with bounded memory.
+func (l *Lexer) Tokens() iter.Seq[token.Token] {
+	return func(yield func(token.Token) bool) {
+		for {
+			t := l.NextToken()
+			if !yield(t) || t.Type == token.Eof {
+				return
+			}
 		}
 	}
-	return tokens
 }
 
 // NextToken returns the next token from the input string.
 func (l *Lexer) NextToken() token.Token {
-	var t token.Token
+	if l.trivia {
+		return l.nextTokenWithTrivia()
+	}
+	return l.nextToken()
+}
+
+// nextTokenWithTrivia reads a token along with its surrounding trivia and its
+// raw source text.
+func (l *Lexer) nextTokenWithTrivia() token.Token {
+	leading := l.readTrivia(false)
 
-	if isWhitespace(l.ch) {
-		return l.handleWhitespace()
+	t := l.nextToken()
+	t.Source = &token.Source{Raw: l.text(), Leading: leading}
+
+	if t.Type != token.Eof {
+		t.Source.Trailing = l.readTrivia(true)
 	}
 
-	switch l.ch {
-	case '=':
-		nextChar, err := l.peekNextChar()
+	return t
+}
 
-		if err != nil {
-			t = token.New(token.Illegal, string(l.ch), l.line, l.col-1)
-			l.readNextChar()
-			break
-		}
+// readTrivia reads whitespace, newlines and comments. Trailing trivia stops
+// after the first newline so that the next line belongs to the next token.
+func (l *Lexer) readTrivia(trailing bool) []token.Trivia {
+	var trivia []token.Trivia
 
-		if nextChar == '=' {
-			l.readNextChar()
-			t = token.New(token.Equal, "==", l.line, l.col-2)
-		} else {
-			t = token.New(token.Assign, "=", l.line, l.col-1)
-		}
-		l.readNextChar()
-	case '+':
-		t = token.New(token.Plus, "+", l.line, l.col-1)
-		l.readNextChar()
-	case '-':
-		t = token.New(token.Minus, "-", l.line, l.col-1)
-		l.readNextChar()
-	case '*':
-		t = token.New(token.Multiply, "*", l.line, l.col-1)
-		l.readNextChar()
-	case '/':
-		t = token.New(token.Divide, "/", l.line, l.col-1)
-		l.readNextChar()
-	case ',':
-		t = token.New(token.Comma, ",", l.line, l.col-1)
-		l.readNextChar()
-	case '.':
-		t = token.New(token.FullStop, ".", l.line, l.col-1)
-		l.readNextChar()
-	case ';':
-		t = token.New(token.Semicolon, ";", l.line, l.col-1)
-		l.readNextChar()
-	case ':':
-		t = token.New(token.Colon, ":", l.line, l.col-1)
-		l.readNextChar()
-	case '(':
-		t = token.New(token.LParen, "(", l.line, l.col-1)
-		l.readNextChar()
-	case ')':
-		t = token.New(token.RParen, ")", l.line, l.col-1)
-		l.readNextChar()
-	case '{':
-		t = token.New(token.LBrace, "{", l.line, l.col-1)
-		l.readNextChar()
-	case '}':
-		t = token.New(token.RBrace, "}", l.line, l.col-1)
-		l.readNextChar()
-	case '[':
-		t = token.New(token.LBracket, "[", l.line, l.col-1)
-		l.readNextChar()
-	case ']':
-		t = token.New(token.RBracket, "]", l.line, l.col-1)
-		l.readNextChar()
-	case '%':
-		t = token.New(token.Percent, "%", l.line, l.col-1)
-		l.readNextChar()
-	case '>':
-		nextChar, err := l.peekNextChar()
+	for {
+		l.beginToken()
 
-		if err != nil {
-			t = token.New(token.Illegal, string(l.ch), l.line, l.col-1)
+		var kind token.TriviaKind
+		switch {
+		case l.ch == '\n':
+			kind = token.Newline
 			l.readNextChar()
-			break
+		case isWhitespace(l.ch):
+			kind = token.Whitespace
+			for isWhitespace(l.ch) && l.ch != '\n' {
+				l.readNextChar()
+			}
+		case l.isCommentStart():
+			kind = token.Comment
+			l.skipComment()
+		default:
+			return trivia
 		}
 
-		if nextChar == '=' {
-			l.readNextChar()
-			t = token.New(token.GreaterThanOrEqual, ">=", l.line, l.col-2)
-		} else {
-			t = token.New(token.GreaterThan, ">", l.line, l.col-1)
-		}
-		l.readNextChar()
-	case '<':
-		nextChar, err := l.peekNextChar()
+		trivia = append(trivia, token.Trivia{Kind: kind, Text: l.text(), Start: l.start, End: l.pos()})
 
-		if err != nil {
-			t = token.New(token.Illegal, string(l.ch), l.line, l.col-1)
-			l.readNextChar()
-			break
+		if trailing && kind == token.Newline {
+			return trivia
 		}
+	}
+}
 
-		if nextChar == '=' {
-			l.readNextChar()
-			t = token.New(token.LessThanOrEqual, "<=", l.line, l.col-2)
-		} else {
-			t = token.New(token.LessThan, "<", l.line, l.col-1)
-		}
+// isCommentStart reports whether the current character begins a // comment.
+func (l *Lexer) isCommentStart() bool {
+	if l.ch != '/' {
+		return false
+	}
+	return l.peekChar(1) == '/'
+}
+
+// skipComment consumes a // comment up to, but not including, the newline.
+func (l *Lexer) skipComment() {
+	for l.ch != '\n' && !l.atEnd() {
 		l.readNextChar()
-	case '!':
-		nextChar, err := l.peekNextChar()
+	}
+}
 
-		if err != nil {
-			t = token.New(token.Illegal, string(l.ch), l.line, l.col-1)
-			l.readNextChar()
-			break
-		}
+func (l *Lexer) nextToken() token.Token {
+	var t token.Token
 
-		if nextChar == '=' {
-			l.readNextChar()
-			t = token.New(token.NotEqual, "!=", l.line, l.col-2)
-		} else {
-			t = token.New(token.Not, "!", l.line, l.col-1)
-		}
-		l.readNextChar()
-	case '&':
-		nextChar, err := l.peekNextChar()
+	l.skipWhitespace()
+	l.beginToken()
 
-		if err != nil {
-			t = token.New(token.Illegal, string(l.ch), l.line, l.col-1)
-			l.readNextChar()
-			break
-		}
+	if l.atEnd() {
+		return l.handleEof()
+	}
 
-		if nextChar == '&' {
-			l.readNextChar()
-			t = token.New(token.And, "&&", l.line, l.col-2)
-		} else {
-			t = token.New(token.Illegal, string(l.ch), l.line, l.col-1)
-		}
-		l.readNextChar()
-	case '|':
-		nextChar, err := l.peekNextChar()
+	// As in JavaScript, a decimal may start with its point, as in .5.
+	if l.ch == '.' && isDecimalDigit(l.peekChar(1)) {
+		return l.handleNumber(t)
+	}
 
-		if err != nil {
-			t = token.New(token.Illegal, string(l.ch), l.line, l.col-1)
-			l.readNextChar()
-			break
-		}
+	if candidates, ok := l.operators[l.ch]; ok {
+		return l.readOperator(candidates)
+	}
 
-		if nextChar == '|' {
-			l.readNextChar()
-			t = token.New(token.Or, "||", l.line, l.col-2)
-		} else {
-			t = token.New(token.Illegal, string(l.ch), l.line, l.col-1)
-		}
-		l.readNextChar()
-	case '"':
-		str, err := l.readString('"')
-		if err {
-			t = token.New(token.Illegal, str, l.line, l.col-utf8.RuneCountInString(str)-1)
-			l.col--
-		} else {
-			t = token.New(token.String, str, l.line, l.col-utf8.RuneCountInString(str)-2)
-		}
-		l.readNextChar()
-	case '\'':
-		str, err := l.readString('\'')
-		if err {
-			t = token.New(token.Illegal, str, l.line, l.col-utf8.RuneCountInString(str)-1)
-			l.col--
-		} else {
-			t = token.New(token.String, str, l.line, l.col-utf8.RuneCountInString(str)-2)
-		}
-		l.readNextChar()
-	case 0:
-		t = l.handleEof()
+	switch l.ch {
+	case '"', '\'':
+		t = l.handleString()
 	default:
 		t = l.handleDefaultCase(t)
 	}
@@ -259,35 +290,74 @@ func (l *Lexer) NextToken() token.Token {
 	return t
 }
 
+// beginToken marks the current character as the start of a token.
+func (l *Lexer) beginToken() {
+	l.start = l.pos()
+}
+
+// text returns the source text from the start of the current token up to the
+// current character.
+func (l *Lexer) text() string {
+	if l.reader == nil {
+		return l.input[l.start.Offset:l.offset]
+	}
+	return string(l.buf[l.start.Offset-l.bufOffset : l.offset-l.bufOffset])
+}
+
+// pos returns the position of the current character.
+func (l *Lexer) pos() token.Pos {
+	return token.Pos{File: l.file, Offset: l.offset, Line: l.line, Column: l.col}
+}
+
+// newToken creates a token spanning from the start of the current token to
+// the current character.
+func (l *Lexer) newToken(t token.Type, v string) token.Token {
+	return token.New(t, v, l.start, l.pos())
+}
+
+func (l *Lexer) handleString() token.Token {
+	if !l.readString(l.ch) {
+		l.addError(UnterminatedString, "unterminated string")
+		return l.newToken(token.Illegal, l.text())
+	}
+
+	// Strip the quotes, which are a single byte each.
+	str := l.text()
+	return l.newToken(token.String, str[1:len(str)-1])
+}
+
 func (l *Lexer) handleEof() token.Token {
-	return token.New(token.Eof, "", l.line, l.col-1)
+	return l.newToken(token.Eof, "")
 }
 
 func (l *Lexer) handleDefaultCase(t token.Token) token.Token {
 	switch {
-	case isLetter(l.ch):
+	case isIdentifierStart(l.ch):
 		t = l.handleIdentifier(t)
-	case unicode.IsDigit(l.ch):
+	case isDecimalDigit(l.ch):
 		t = l.handleNumber(t)
 	default:
-		t = l.handleIllegalRune(t)
+		t = l.handleIllegalRune()
 	}
 	return t
 }
 
-func (l *Lexer) handleIllegalRune(t token.Token) token.Token {
-	t = token.New(token.Illegal, string(l.ch), l.line, l.col-1)
+func (l *Lexer) handleIllegalRune() token.Token {
+	ch := l.ch
 	l.readNextChar()
-	return t
+	l.addError(InvalidCharacter, fmt.Sprintf("invalid character %q", ch))
+	return l.newToken(token.Illegal, l.text())
 }
 
 func (l *Lexer) handleNumber(t token.Token) token.Token {
-	num, err := l.readNumber()
+	err := l.readNumber()
+	num := l.text()
 
 	if err == nil {
-		t = token.New(token.Number, num, l.line, l.col-utf8.RuneCountInString(num)-1)
+		t = l.newToken(token.Number, num)
 	} else {
-		t = token.New(token.Illegal, num, l.line, l.col-utf8.RuneCountInString(num)-1)
+		t = l.newToken(token.Illegal, num)
+		l.addError(MalformedNumber, fmt.Sprintf("malformed number %q: %s", num, err))
 	}
 	return t
 }
@@ -297,103 +367,288 @@ func (l *Lexer) handleIdentifier(t token.Token) token.Token {
 	return t
 }
 
-func (l *Lexer) handleWhitespace() token.Token {
-	if l.ch == '\n' {
-		l.line++
-		l.col = 0
+// skipWhitespace skips whitespace and comments in a loop, so that long runs
+// of blank space do not grow the stack.
+func (l *Lexer) skipWhitespace() {
+	for {
+		switch {
+		case isWhitespace(l.ch):
+			l.readNextChar()
+		case l.isCommentStart():
+			l.skipComment()
+		default:
+			return
+		}
 	}
-	l.readNextChar()
-	return l.NextToken()
 }
 
+// readIdentifier reads an identifier made of an XID_Start rune followed by
+// XID_Continue runes. The value is NFC normalised so that visually identical
+// names written with different code point sequences bind to the same variable.
 func (l *Lexer) readIdentifier() token.Token {
-	var builder strings.Builder
-	for unicode.IsLetter(l.ch) || l.ch == '_' {
-		builder.WriteRune(l.ch)
+	for isIdentifierContinue(l.ch) {
 		l.readNextChar()
 	}
-	identifier := builder.String()
+	identifier := norm.NFC.String(l.text())
 
-	return token.New(token.GetKeywordType(identifier), identifier, l.line, l.col-utf8.RuneCountInString(identifier)-1)
-}
+	if scripts := mixedScripts(identifier); scripts != nil {
+		l.addWarning(CodeConfusableIdentifier, fmt.Sprintf("identifier %q mixes %s characters, which may be confusable",
+			identifier, strings.Join(scripts, " and ")))
+	}
 
-func (l *Lexer) readNumber() (string, error) {
-	var builder strings.Builder
-	haveReadDot := false
+	return l.newToken(l.dialect.KeywordType(identifier), identifier)
+}
 
-	for {
-		// Check if the current character is not a digit and not a valid dot condition.
-		if !unicode.IsDigit(l.ch) && (l.ch != '.' || haveReadDot) {
-			break
-		}
+// readNumber reads a numeric literal. Besides plain decimals it accepts
+// hexadecimal (0xFF), octal (0o17) and binary (0b1010) integers, underscore
+// digit separators (1_000_000), exponents (1.5e-3) and the decimal suffix
+// (19.99m). On error everything consumed so far is left as the token text, so
+// the caller can report the whole malformed literal.
+func (l *Lexer) readNumber() error {
+	if l.ch == '0' {
+		if isDigit, name := baseDigitFn(l.peekChar(1)); isDigit != nil {
+			l.readNextChar()
+			l.readNextChar()
 
-		// Handle dot followed by a digit
-		if l.ch == '.' {
-			nextChar, err := l.peekNextChar()
+			count, err := l.readDigits(isDigit)
 			if err != nil {
-				if errors.Is(err, io.EOF) {
-					// If EOF, it's a valid end of number, break the loop
-					break
-				}
-				// Return error immediately if not EOF
-				return "", err
+				return err
 			}
+			if count == 0 {
+				return fmt.Errorf("%s literal has no digits", name)
+			}
+			return nil
+		}
+	}
 
-			if !unicode.IsDigit(nextChar) {
-				break
+	if _, err := l.readDigits(isDecimalDigit); err != nil {
+		return err
+	}
+
+	// Only treat a dot as a decimal point if a digit follows it, so that
+	// "5.method" still lexes as a number followed by a full stop.
+	if l.ch == '.' {
+		if isDecimalDigit(l.peekChar(1)) {
+			l.readNextChar()
+			if _, err := l.readDigits(isDecimalDigit); err != nil {
+				return err
 			}
+		}
+	}
+
+	if l.ch == 'e' || l.ch == 'E' {
+		l.readNextChar()
+
+		if l.ch == '+' || l.ch == '-' {
+			l.readNextChar()
+		}
 
-			haveReadDot = true
+		count, err := l.readDigits(isDecimalDigit)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return errors.New("exponent has no digits")
 		}
+	}
 
-		builder.WriteRune(l.ch)
+	// An m suffix marks an exact decimal literal, e.g. 19.99m, unless it
+	// begins a longer identifier such as the unit in 5min.
+	if l.ch == 'm' && !isIdentifierContinue(l.peekChar(1)) {
 		l.readNextChar()
 	}
 
-	str := builder.String()
+	return nil
+}
 
-	// Check for invalid number format
-	if l.numEndsWithDot(str, haveReadDot) {
-		return str, errors.New("number ends with a dot")
+// readDigits reads a run of digits accepted by isDigit, allowing single
+// underscores between them. It returns the number of digits read.
+func (l *Lexer) readDigits(isDigit func(rune) bool) (int, error) {
+	count := 0
+	lastWasUnderscore := false
+	var err error
+
+	for isDigit(l.ch) || l.ch == '_' {
+		if l.ch == '_' {
+			if lastWasUnderscore && err == nil {
+				err = errors.New("consecutive underscores in number")
+			}
+			lastWasUnderscore = true
+		} else {
+			count++
+			lastWasUnderscore = false
+		}
+		l.readNextChar()
 	}
 
-	// Return the number string and no error
-	return str, nil
+	if err != nil {
+		return count, err
+	}
+
+	if lastWasUnderscore {
+		return count, errors.New("number ends with an underscore")
+	}
+
+	return count, nil
 }
 
-func (l *Lexer) numEndsWithDot(str string, haveReadDot bool) bool {
-	notEmpty := len(str) > 0
-	lastCharIsDot := str[len(str)-1] == '.'
-	endsWithDot := haveReadDot && notEmpty && lastCharIsDot
-	return endsWithDot
+// baseDigitFn returns the digit predicate and a descriptive name for the base
+// introduced by the given prefix character, or nil if it is not a base prefix.
+func baseDigitFn(prefix rune) (func(rune) bool, string) {
+	switch prefix {
+	case 'x', 'X':
+		return isHexDigit, "hexadecimal"
+	case 'o', 'O':
+		return isOctalDigit, "octal"
+	case 'b', 'B':
+		return isBinaryDigit, "binary"
+	default:
+		return nil, ""
+	}
 }
 
-func (l *Lexer) readString(ch rune) (string, bool) {
-	var builder strings.Builder
+// readString reads a string delimited by quote, including both quotes. It
+// reports false if the input ends before the closing quote.
+func (l *Lexer) readString(quote rune) bool {
+	l.readNextChar()
 
-	initialQuote := l.ch
+	for l.ch != quote {
+		if l.atEnd() {
+			return false
+		}
+		l.readNextChar()
+	}
 
+	// Consume the closing quote.
 	l.readNextChar()
+	return true
+}
 
-	for {
-		if l.ch == ch || l.ch == 0 {
-			break
+// isIdentifierStart approximates the Unicode XID_Start property, with the
+// addition of the underscore.
+func isIdentifierStart(ch rune) bool {
+	return ch == '_' ||
+		unicode.IsLetter(ch) ||
+		unicode.Is(unicode.Nl, ch) ||
+		unicode.Is(unicode.Other_ID_Start, ch)
+}
+
+// isIdentifierContinue approximates the Unicode XID_Continue property.
+func isIdentifierContinue(ch rune) bool {
+	return isIdentifierStart(ch) ||
+		unicode.Is(unicode.Mn, ch) ||
+		unicode.Is(unicode.Mc, ch) ||
+		unicode.Is(unicode.Nd, ch) ||
+		unicode.Is(unicode.Pc, ch) ||
+		unicode.Is(unicode.Other_ID_Continue, ch)
+}
+
+// mixedScripts returns the names of the scripts used by the letters in the
+// identifier, in order of first appearance, if they mix in a way that may be
+// confusable. Common and Inherited runes, such as digits and combining marks,
+// are ignored. As in the Highly Restrictive level of UTS #39, Han may be
+// mixed with Hiragana and Katakana, Bopomofo or Hangul, and Latin with any
+// of those combinations, as is usual in Japanese, Chinese and Korean.
+func mixedScripts(identifier string) []string {
+	var scripts []string
+
+	for _, ch := range identifier {
+		if !unicode.IsLetter(ch) {
+			continue
+		}
+		name := scriptOf(ch)
+		if name != "" && !slices.Contains(scripts, name) {
+			scripts = append(scripts, name)
 		}
-		builder.WriteRune(l.ch)
-		l.readNextChar()
 	}
 
-	s := builder.String()
+	if len(scripts) < 2 {
+		return nil
+	}
+	for _, allowed := range allowedScriptMixes {
+		if isSubset(scripts, allowed) {
+			return nil
+		}
+	}
+	return scripts
+}
+
+// allowedScriptMixes are the combinations of scripts that UTS #39 accepts at
+// its Highly Restrictive level.
+var allowedScriptMixes = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+// isSubset reports whether every element of s is in set.
+func isSubset(s, set []string) bool {
+	for _, v := range s {
+		if !slices.Contains(set, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// script is a Unicode script and the table of its characters.
+type script struct {
+	name  string
+	table *unicode.RangeTable
+}
+
+// scriptTables lists the scripts scriptOf looks letters up in, without the Common
+// and Inherited pseudo-scripts. Those most often seen in identifiers come
+// first, and the rest follow by name.
+var scriptTables = func() []script {
+	first := []string{"Latin", "Greek", "Cyrillic", "Han", "Hiragana", "Katakana", "Hangul", "Arabic", "Hebrew"}
 
-	if l.ch == 0 {
-		s = string(initialQuote) + builder.String()
+	var list []script
+	for _, name := range first {
+		list = append(list, script{name, unicode.Scripts[name]})
 	}
+	var rest []script
+	for name, table := range unicode.Scripts {
+		if name != "Common" && name != "Inherited" && !slices.Contains(first, name) {
+			rest = append(rest, script{name, table})
+		}
+	}
+	slices.SortFunc(rest, func(a, b script) int {
+		return strings.Compare(a.name, b.name)
+	})
+	return append(list, rest...)
+}()
+
+// scriptOf returns the name of the script the letter belongs to, ignoring the
+// Common and Inherited pseudo-scripts.
+func scriptOf(ch rune) string {
+	if ch < utf8.RuneSelf {
+		return "Latin"
+	}
+
+	for _, s := range scriptTables {
+		if unicode.Is(s.table, ch) {
+			return s.name
+		}
+	}
+
+	return ""
+}
+
+func isDecimalDigit(ch rune) bool {
+	return '0' <= ch && ch <= '9'
+}
+
+func isHexDigit(ch rune) bool {
+	return isDecimalDigit(ch) || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
+}
 
-	return s, l.ch == 0
+func isOctalDigit(ch rune) bool {
+	return '0' <= ch && ch <= '7'
 }
 
-func isLetter(ch rune) bool {
-	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+func isBinaryDigit(ch rune) bool {
+	return ch == '0' || ch == '1'
 }
 
 func isWhitespace(ch rune) bool {
@@ -1,11 +1,17 @@
 package lexer
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"lang/lexer/token"
 	"os"
 	"reflect"
 	"strings"
 	"testing"
+	"testing/iotest"
+	"unsafe"
 )
 
 func TestLexer_Tokenize_FromString(t *testing.T) {
@@ -19,16 +25,16 @@ func TestLexer_Tokenize_FromString(t *testing.T) {
 			input: ";",
 			expected: []token.Token{
 				{
-					Type:   token.Semicolon,
-					Value:  ";",
-					Line:   0,
-					Column: 0,
+					Type:  token.Semicolon,
+					Value: ";",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 1, Line: 1, Column: 2},
 				},
 				{
-					Type:   token.Eof,
-					Value:  "",
-					Line:   0,
-					Column: 1,
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 1, Line: 1, Column: 2},
+					End:   token.Pos{Offset: 1, Line: 1, Column: 2},
 				},
 			},
 		},
@@ -37,16 +43,16 @@ func TestLexer_Tokenize_FromString(t *testing.T) {
 			input: ">=",
 			expected: []token.Token{
 				{
-					Type:   token.GreaterThanOrEqual,
-					Value:  ">=",
-					Line:   0,
-					Column: 0,
+					Type:  token.GreaterThanOrEqual,
+					Value: ">=",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 2, Line: 1, Column: 3},
 				},
 				{
-					Type:   token.Eof,
-					Value:  "",
-					Line:   0,
-					Column: 2,
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 2, Line: 1, Column: 3},
+					End:   token.Pos{Offset: 2, Line: 1, Column: 3},
 				},
 			},
 		},
@@ -55,16 +61,16 @@ func TestLexer_Tokenize_FromString(t *testing.T) {
 			input: "<=",
 			expected: []token.Token{
 				{
-					Type:   token.LessThanOrEqual,
-					Value:  "<=",
-					Line:   0,
-					Column: 0,
+					Type:  token.LessThanOrEqual,
+					Value: "<=",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 2, Line: 1, Column: 3},
 				},
 				{
-					Type:   token.Eof,
-					Value:  "",
-					Line:   0,
-					Column: 2,
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 2, Line: 1, Column: 3},
+					End:   token.Pos{Offset: 2, Line: 1, Column: 3},
 				},
 			},
 		},
@@ -73,16 +79,16 @@ func TestLexer_Tokenize_FromString(t *testing.T) {
 			input: "123",
 			expected: []token.Token{
 				{
-					Type:   token.Number,
-					Value:  "123",
-					Line:   0,
-					Column: 0,
+					Type:  token.Number,
+					Value: "123",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 3, Line: 1, Column: 4},
 				},
 				{
-					Type:   token.Eof,
-					Value:  "",
-					Line:   0,
-					Column: 3,
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 3, Line: 1, Column: 4},
+					End:   token.Pos{Offset: 3, Line: 1, Column: 4},
 				},
 			},
 		},
@@ -91,16 +97,178 @@ func TestLexer_Tokenize_FromString(t *testing.T) {
 			input: "0.123",
 			expected: []token.Token{
 				{
-					Type:   token.Number,
-					Value:  "0.123",
-					Line:   0,
-					Column: 0,
+					Type:  token.Number,
+					Value: "0.123",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 5, Line: 1, Column: 6},
 				},
 				{
-					Type:   token.Eof,
-					Value:  "",
-					Line:   0,
-					Column: 5,
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 5, Line: 1, Column: 6},
+					End:   token.Pos{Offset: 5, Line: 1, Column: 6},
+				},
+			},
+		},
+		{
+			name:  "Hexadecimal number token",
+			input: "0xFF",
+			expected: []token.Token{
+				{
+					Type:  token.Number,
+					Value: "0xFF",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 4, Line: 1, Column: 5},
+				},
+				{
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 4, Line: 1, Column: 5},
+					End:   token.Pos{Offset: 4, Line: 1, Column: 5},
+				},
+			},
+		},
+		{
+			name:  "Octal number token",
+			input: "0o17",
+			expected: []token.Token{
+				{
+					Type:  token.Number,
+					Value: "0o17",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 4, Line: 1, Column: 5},
+				},
+				{
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 4, Line: 1, Column: 5},
+					End:   token.Pos{Offset: 4, Line: 1, Column: 5},
+				},
+			},
+		},
+		{
+			name:  "Binary number token",
+			input: "0b1010",
+			expected: []token.Token{
+				{
+					Type:  token.Number,
+					Value: "0b1010",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 6, Line: 1, Column: 7},
+				},
+				{
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 6, Line: 1, Column: 7},
+					End:   token.Pos{Offset: 6, Line: 1, Column: 7},
+				},
+			},
+		},
+		{
+			name:  "Number with digit separators",
+			input: "1_000_000",
+			expected: []token.Token{
+				{
+					Type:  token.Number,
+					Value: "1_000_000",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 9, Line: 1, Column: 10},
+				},
+				{
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 9, Line: 1, Column: 10},
+					End:   token.Pos{Offset: 9, Line: 1, Column: 10},
+				},
+			},
+		},
+		{
+			name:  "Number with exponent",
+			input: "1.5e-3",
+			expected: []token.Token{
+				{
+					Type:  token.Number,
+					Value: "1.5e-3",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 6, Line: 1, Column: 7},
+				},
+				{
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 6, Line: 1, Column: 7},
+					End:   token.Pos{Offset: 6, Line: 1, Column: 7},
+				},
+			},
+		},
+		{
+			name:  "Hexadecimal prefix without digits",
+			input: "0x",
+			expected: []token.Token{
+				{
+					Type:  token.Illegal,
+					Value: "0x",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 2, Line: 1, Column: 3},
+				},
+				{
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 2, Line: 1, Column: 3},
+					End:   token.Pos{Offset: 2, Line: 1, Column: 3},
+				},
+			},
+		},
+		{
+			name:  "Number with consecutive underscores",
+			input: "1__0",
+			expected: []token.Token{
+				{
+					Type:  token.Illegal,
+					Value: "1__0",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 4, Line: 1, Column: 5},
+				},
+				{
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 4, Line: 1, Column: 5},
+					End:   token.Pos{Offset: 4, Line: 1, Column: 5},
+				},
+			},
+		},
+		{
+			name:  "Number with trailing underscore",
+			input: "1_",
+			expected: []token.Token{
+				{
+					Type:  token.Illegal,
+					Value: "1_",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 2, Line: 1, Column: 3},
+				},
+				{
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 2, Line: 1, Column: 3},
+					End:   token.Pos{Offset: 2, Line: 1, Column: 3},
+				},
+			},
+		},
+		{
+			name:  "Exponent without digits",
+			input: "1e",
+			expected: []token.Token{
+				{
+					Type:  token.Illegal,
+					Value: "1e",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 2, Line: 1, Column: 3},
+				},
+				{
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 2, Line: 1, Column: 3},
+					End:   token.Pos{Offset: 2, Line: 1, Column: 3},
 				},
 			},
 		},
@@ -109,34 +277,88 @@ func TestLexer_Tokenize_FromString(t *testing.T) {
 			input: "ident",
 			expected: []token.Token{
 				{
-					Type:   token.Ident,
-					Value:  "ident",
-					Line:   0,
-					Column: 0,
+					Type:  token.Ident,
+					Value: "ident",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 5, Line: 1, Column: 6},
+				},
+				{
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 5, Line: 1, Column: 6},
+					End:   token.Pos{Offset: 5, Line: 1, Column: 6},
+				},
+			},
+		},
+		{
+			name:  "Identifier containing digits",
+			input: "x1",
+			expected: []token.Token{
+				{
+					Type:  token.Ident,
+					Value: "x1",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 2, Line: 1, Column: 3},
 				},
 				{
-					Type:   token.Eof,
-					Value:  "",
-					Line:   0,
-					Column: 5,
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 2, Line: 1, Column: 3},
+					End:   token.Pos{Offset: 2, Line: 1, Column: 3},
+				},
+			},
+		},
+		{
+			name:  "Unicode identifier",
+			input: "Größe",
+			expected: []token.Token{
+				{
+					Type:  token.Ident,
+					Value: "Größe",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 7, Line: 1, Column: 6},
+				},
+				{
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 7, Line: 1, Column: 6},
+					End:   token.Pos{Offset: 7, Line: 1, Column: 6},
+				},
+			},
+		},
+		{
+			name:  "Identifier is NFC normalised",
+			input: "cafe\u0301",
+			expected: []token.Token{
+				{
+					Type:  token.Ident,
+					Value: "caf\u00e9",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 6, Line: 1, Column: 6},
+				},
+				{
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 6, Line: 1, Column: 6},
+					End:   token.Pos{Offset: 6, Line: 1, Column: 6},
 				},
 			},
 		},
 		{
 			name:  "Illegal character",
-			input: "&",
+			input: "#",
 			expected: []token.Token{
 				{
-					Type:   token.Illegal,
-					Value:  "&",
-					Line:   0,
-					Column: 0,
+					Type:  token.Illegal,
+					Value: "#",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 1, Line: 1, Column: 2},
 				},
 				{
-					Type:   token.Eof,
-					Value:  "",
-					Line:   0,
-					Column: 1,
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 1, Line: 1, Column: 2},
+					End:   token.Pos{Offset: 1, Line: 1, Column: 2},
 				},
 			},
 		},
@@ -145,22 +367,22 @@ func TestLexer_Tokenize_FromString(t *testing.T) {
 			input: "\"a string\"test",
 			expected: []token.Token{
 				{
-					Type:   token.String,
-					Value:  "a string",
-					Line:   0,
-					Column: 0,
+					Type:  token.String,
+					Value: "a string",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 10, Line: 1, Column: 11},
 				},
 				{
-					Type:   token.Ident,
-					Value:  "test",
-					Line:   0,
-					Column: 10,
+					Type:  token.Ident,
+					Value: "test",
+					Start: token.Pos{Offset: 10, Line: 1, Column: 11},
+					End:   token.Pos{Offset: 14, Line: 1, Column: 15},
 				},
 				{
-					Type:   token.Eof,
-					Value:  "",
-					Line:   0,
-					Column: 14,
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 14, Line: 1, Column: 15},
+					End:   token.Pos{Offset: 14, Line: 1, Column: 15},
 				},
 			},
 		},
@@ -169,16 +391,16 @@ func TestLexer_Tokenize_FromString(t *testing.T) {
 			input: "\"test",
 			expected: []token.Token{
 				{
-					Type:   token.Illegal,
-					Value:  "\"test",
-					Line:   0,
-					Column: 0,
+					Type:  token.Illegal,
+					Value: "\"test",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 5, Line: 1, Column: 6},
 				},
 				{
-					Type:   token.Eof,
-					Value:  "",
-					Line:   0,
-					Column: 5,
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 5, Line: 1, Column: 6},
+					End:   token.Pos{Offset: 5, Line: 1, Column: 6},
 				},
 			},
 		},
@@ -187,22 +409,22 @@ func TestLexer_Tokenize_FromString(t *testing.T) {
 			input: "'a string'test",
 			expected: []token.Token{
 				{
-					Type:   token.String,
-					Value:  "a string",
-					Line:   0,
-					Column: 0,
+					Type:  token.String,
+					Value: "a string",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 10, Line: 1, Column: 11},
 				},
 				{
-					Type:   token.Ident,
-					Value:  "test",
-					Line:   0,
-					Column: 10,
+					Type:  token.Ident,
+					Value: "test",
+					Start: token.Pos{Offset: 10, Line: 1, Column: 11},
+					End:   token.Pos{Offset: 14, Line: 1, Column: 15},
 				},
 				{
-					Type:   token.Eof,
-					Value:  "",
-					Line:   0,
-					Column: 14,
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 14, Line: 1, Column: 15},
+					End:   token.Pos{Offset: 14, Line: 1, Column: 15},
 				},
 			},
 		},
@@ -211,16 +433,16 @@ func TestLexer_Tokenize_FromString(t *testing.T) {
 			input: "'a string++4",
 			expected: []token.Token{
 				{
-					Type:   token.Illegal,
-					Value:  "'a string++4",
-					Line:   0,
-					Column: 0,
+					Type:  token.Illegal,
+					Value: "'a string++4",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 12, Line: 1, Column: 13},
 				},
 				{
-					Type:   token.Eof,
-					Value:  "",
-					Line:   0,
-					Column: 12,
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 12, Line: 1, Column: 13},
+					End:   token.Pos{Offset: 12, Line: 1, Column: 13},
 				},
 			},
 		},
@@ -229,46 +451,46 @@ func TestLexer_Tokenize_FromString(t *testing.T) {
 			input: "ident_with_underscores;&/ident.",
 			expected: []token.Token{
 				{
-					Type:   token.Ident,
-					Value:  "ident_with_underscores",
-					Line:   0,
-					Column: 0,
+					Type:  token.Ident,
+					Value: "ident_with_underscores",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 22, Line: 1, Column: 23},
 				},
 				{
-					Type:   token.Semicolon,
-					Value:  ";",
-					Line:   0,
-					Column: 22,
+					Type:  token.Semicolon,
+					Value: ";",
+					Start: token.Pos{Offset: 22, Line: 1, Column: 23},
+					End:   token.Pos{Offset: 23, Line: 1, Column: 24},
 				},
 				{
-					Type:   token.Illegal,
-					Value:  "&",
-					Line:   0,
-					Column: 23,
+					Type:  token.BitAnd,
+					Value: "&",
+					Start: token.Pos{Offset: 23, Line: 1, Column: 24},
+					End:   token.Pos{Offset: 24, Line: 1, Column: 25},
 				},
 				{
-					Type:   token.Divide,
-					Value:  "/",
-					Line:   0,
-					Column: 24,
+					Type:  token.Divide,
+					Value: "/",
+					Start: token.Pos{Offset: 24, Line: 1, Column: 25},
+					End:   token.Pos{Offset: 25, Line: 1, Column: 26},
 				},
 				{
-					Type:   token.Ident,
-					Value:  "ident",
-					Line:   0,
-					Column: 25,
+					Type:  token.Ident,
+					Value: "ident",
+					Start: token.Pos{Offset: 25, Line: 1, Column: 26},
+					End:   token.Pos{Offset: 30, Line: 1, Column: 31},
 				},
 				{
-					Type:   token.FullStop,
-					Value:  ".",
-					Line:   0,
-					Column: 30,
+					Type:  token.FullStop,
+					Value: ".",
+					Start: token.Pos{Offset: 30, Line: 1, Column: 31},
+					End:   token.Pos{Offset: 31, Line: 1, Column: 32},
 				},
 				{
-					Type:   token.Eof,
-					Value:  "",
-					Line:   0,
-					Column: 31,
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 31, Line: 1, Column: 32},
+					End:   token.Pos{Offset: 31, Line: 1, Column: 32},
 				},
 			},
 		},
@@ -277,46 +499,106 @@ func TestLexer_Tokenize_FromString(t *testing.T) {
 			input: "ident_with_underscores; & / ident.",
 			expected: []token.Token{
 				{
-					Type:   token.Ident,
-					Value:  "ident_with_underscores",
-					Line:   0,
-					Column: 0,
+					Type:  token.Ident,
+					Value: "ident_with_underscores",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 22, Line: 1, Column: 23},
+				},
+				{
+					Type:  token.Semicolon,
+					Value: ";",
+					Start: token.Pos{Offset: 22, Line: 1, Column: 23},
+					End:   token.Pos{Offset: 23, Line: 1, Column: 24},
+				},
+				{
+					Type:  token.BitAnd,
+					Value: "&",
+					Start: token.Pos{Offset: 24, Line: 1, Column: 25},
+					End:   token.Pos{Offset: 25, Line: 1, Column: 26},
+				},
+				{
+					Type:  token.Divide,
+					Value: "/",
+					Start: token.Pos{Offset: 26, Line: 1, Column: 27},
+					End:   token.Pos{Offset: 27, Line: 1, Column: 28},
+				},
+				{
+					Type:  token.Ident,
+					Value: "ident",
+					Start: token.Pos{Offset: 28, Line: 1, Column: 29},
+					End:   token.Pos{Offset: 33, Line: 1, Column: 34},
+				},
+				{
+					Type:  token.FullStop,
+					Value: ".",
+					Start: token.Pos{Offset: 33, Line: 1, Column: 34},
+					End:   token.Pos{Offset: 34, Line: 1, Column: 35},
+				},
+				{
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 34, Line: 1, Column: 35},
+					End:   token.Pos{Offset: 34, Line: 1, Column: 35},
+				},
+			},
+		},
+		{
+			name:  "Number followed by an identifier starting with m",
+			input: "5min",
+			expected: []token.Token{
+				{
+					Type:  token.Number,
+					Value: "5",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 1, Line: 1, Column: 2},
 				},
 				{
-					Type:   token.Semicolon,
-					Value:  ";",
-					Line:   0,
-					Column: 22,
+					Type:  token.Ident,
+					Value: "min",
+					Start: token.Pos{Offset: 1, Line: 1, Column: 2},
+					End:   token.Pos{Offset: 4, Line: 1, Column: 5},
 				},
 				{
-					Type:   token.Illegal,
-					Value:  "&",
-					Line:   0,
-					Column: 24,
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 4, Line: 1, Column: 5},
+					End:   token.Pos{Offset: 4, Line: 1, Column: 5},
+				},
+			},
+		},
+		{
+			name:  "Assignment of a number followed by an identifier starting with m",
+			input: "x = 5max",
+			expected: []token.Token{
+				{
+					Type:  token.Ident,
+					Value: "x",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 1, Line: 1, Column: 2},
 				},
 				{
-					Type:   token.Divide,
-					Value:  "/",
-					Line:   0,
-					Column: 26,
+					Type:  token.Assign,
+					Value: "=",
+					Start: token.Pos{Offset: 2, Line: 1, Column: 3},
+					End:   token.Pos{Offset: 3, Line: 1, Column: 4},
 				},
 				{
-					Type:   token.Ident,
-					Value:  "ident",
-					Line:   0,
-					Column: 28,
+					Type:  token.Number,
+					Value: "5",
+					Start: token.Pos{Offset: 4, Line: 1, Column: 5},
+					End:   token.Pos{Offset: 5, Line: 1, Column: 6},
 				},
 				{
-					Type:   token.FullStop,
-					Value:  ".",
-					Line:   0,
-					Column: 33,
+					Type:  token.Ident,
+					Value: "max",
+					Start: token.Pos{Offset: 5, Line: 1, Column: 6},
+					End:   token.Pos{Offset: 8, Line: 1, Column: 9},
 				},
 				{
-					Type:   token.Eof,
-					Value:  "",
-					Line:   0,
-					Column: 34,
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 8, Line: 1, Column: 9},
+					End:   token.Pos{Offset: 8, Line: 1, Column: 9},
 				},
 			},
 		},
@@ -326,6 +608,14 @@ func TestLexer_Tokenize_FromString(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			l := New(strings.NewReader(tt.input))
 
+			tokens := l.Tokenize()
+			if !reflect.DeepEqual(tokens, tt.expected) {
+				t.Errorf("Tokenize() = %v, want %v", tokens, tt.expected)
+			}
+		})
+		t.Run(tt.name+" from bytes", func(t *testing.T) {
+			l := NewFromBytes([]byte(tt.input))
+
 			tokens := l.Tokenize()
 			if !reflect.DeepEqual(tokens, tt.expected) {
 				t.Errorf("Tokenize() = %v, want %v", tokens, tt.expected)
@@ -345,34 +635,34 @@ func TestLexer_Tokenize_FromFile(t *testing.T) {
 			filePath: "testdata/single_line.txt",
 			expected: []token.Token{
 				{
-					Type:   token.Ident,
-					Value:  "x",
-					Line:   0,
-					Column: 0,
+					Type:  token.Ident,
+					Value: "x",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 1, Line: 1, Column: 2},
 				},
 				{
-					Type:   token.Plus,
-					Value:  "+",
-					Line:   0,
-					Column: 1,
+					Type:  token.Plus,
+					Value: "+",
+					Start: token.Pos{Offset: 1, Line: 1, Column: 2},
+					End:   token.Pos{Offset: 2, Line: 1, Column: 3},
 				},
 				{
-					Type:   token.Number,
-					Value:  "5",
-					Line:   0,
-					Column: 2,
+					Type:  token.Number,
+					Value: "5",
+					Start: token.Pos{Offset: 2, Line: 1, Column: 3},
+					End:   token.Pos{Offset: 3, Line: 1, Column: 4},
 				},
 				{
-					Type:   token.Semicolon,
-					Value:  ";",
-					Line:   0,
-					Column: 3,
+					Type:  token.Semicolon,
+					Value: ";",
+					Start: token.Pos{Offset: 3, Line: 1, Column: 4},
+					End:   token.Pos{Offset: 4, Line: 1, Column: 5},
 				},
 				{
-					Type:   token.Eof,
-					Value:  "",
-					Line:   0,
-					Column: 4,
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 4, Line: 1, Column: 5},
+					End:   token.Pos{Offset: 4, Line: 1, Column: 5},
 				},
 			},
 		},
@@ -381,40 +671,40 @@ func TestLexer_Tokenize_FromFile(t *testing.T) {
 			filePath: "testdata/multiple_lines.txt",
 			expected: []token.Token{
 				{
-					Type:   token.Ident,
-					Value:  "x",
-					Line:   0,
-					Column: 0,
+					Type:  token.Ident,
+					Value: "x",
+					Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+					End:   token.Pos{Offset: 1, Line: 1, Column: 2},
 				},
 				{
-					Type:   token.Assign,
-					Value:  "=",
-					Line:   0,
-					Column: 2,
+					Type:  token.Assign,
+					Value: "=",
+					Start: token.Pos{Offset: 2, Line: 1, Column: 3},
+					End:   token.Pos{Offset: 3, Line: 1, Column: 4},
 				},
 				{
-					Type:   token.Number,
-					Value:  "5",
-					Line:   0,
-					Column: 4,
+					Type:  token.Number,
+					Value: "5",
+					Start: token.Pos{Offset: 4, Line: 1, Column: 5},
+					End:   token.Pos{Offset: 5, Line: 1, Column: 6},
 				},
 				{
-					Type:   token.Semicolon,
-					Value:  ";",
-					Line:   0,
-					Column: 5,
+					Type:  token.Semicolon,
+					Value: ";",
+					Start: token.Pos{Offset: 5, Line: 1, Column: 6},
+					End:   token.Pos{Offset: 6, Line: 1, Column: 7},
 				},
 				{
-					Type:   token.String,
-					Value:  "test    test",
-					Line:   3,
-					Column: 0,
+					Type:  token.String,
+					Value: "test    test",
+					Start: token.Pos{Offset: 9, Line: 4, Column: 1},
+					End:   token.Pos{Offset: 23, Line: 4, Column: 15},
 				},
 				{
-					Type:   token.Eof,
-					Value:  "",
-					Line:   3,
-					Column: 14,
+					Type:  token.Eof,
+					Value: "",
+					Start: token.Pos{Offset: 23, Line: 4, Column: 15},
+					End:   token.Pos{Offset: 23, Line: 4, Column: 15},
 				},
 			},
 		},
@@ -442,3 +732,589 @@ func TestLexer_Tokenize_FromFile(t *testing.T) {
 		})
 	}
 }
+
+func TestLexer_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []*Error
+	}{
+		{
+			name:     "Valid input",
+			input:    "0xFF + 1_000",
+			expected: []*Error{},
+		},
+		{
+			name:  "Hexadecimal prefix without digits",
+			input: "0x",
+			expected: []*Error{
+				{Kind: MalformedNumber, Msg: `malformed number "0x": hexadecimal literal has no digits`, Start: token.Pos{Offset: 0, Line: 1, Column: 1}, End: token.Pos{Offset: 2, Line: 1, Column: 3}},
+			},
+		},
+		{
+			name:  "Consecutive underscores",
+			input: "x + 1__0",
+			expected: []*Error{
+				{Kind: MalformedNumber, Msg: `malformed number "1__0": consecutive underscores in number`, Start: token.Pos{Offset: 4, Line: 1, Column: 5}, End: token.Pos{Offset: 8, Line: 1, Column: 9}},
+			},
+		},
+		{
+			name:  "Exponent without digits",
+			input: "1e;",
+			expected: []*Error{
+				{Kind: MalformedNumber, Msg: `malformed number "1e": exponent has no digits`, Start: token.Pos{Offset: 0, Line: 1, Column: 1}, End: token.Pos{Offset: 2, Line: 1, Column: 3}},
+			},
+		},
+		{
+			name:  "Invalid characters",
+			input: "é + # @ y",
+			expected: []*Error{
+				{Kind: InvalidCharacter, Msg: `invalid character '#'`, Start: token.Pos{Offset: 5, Line: 1, Column: 5}, End: token.Pos{Offset: 6, Line: 1, Column: 6}},
+				{Kind: InvalidCharacter, Msg: `invalid character '@'`, Start: token.Pos{Offset: 7, Line: 1, Column: 7}, End: token.Pos{Offset: 8, Line: 1, Column: 8}},
+			},
+		},
+		{
+			name:  "NUL byte",
+			input: "a\x00b",
+			expected: []*Error{
+				{Kind: InvalidCharacter, Msg: `invalid character '\x00'`, Start: token.Pos{Offset: 1, Line: 1, Column: 2}, End: token.Pos{Offset: 2, Line: 1, Column: 3}},
+			},
+		},
+		{
+			name:     "NUL byte in a string",
+			input:    "'a\x00b'",
+			expected: []*Error{},
+		},
+		{
+			name:  "NUL byte in an unterminated string",
+			input: "'a\x00",
+			expected: []*Error{
+				{Kind: UnterminatedString, Msg: "unterminated string", Start: token.Pos{Offset: 0, Line: 1, Column: 1}, End: token.Pos{Offset: 3, Line: 1, Column: 4}},
+			},
+		},
+		{
+			name:  "Unterminated string",
+			input: "x\n'abc",
+			expected: []*Error{
+				{Kind: UnterminatedString, Msg: "unterminated string", Start: token.Pos{Offset: 2, Line: 2, Column: 1}, End: token.Pos{Offset: 6, Line: 2, Column: 5}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(strings.NewReader(tt.input))
+			l.Tokenize()
+
+			if !reflect.DeepEqual(l.Errors(), tt.expected) {
+				t.Errorf("Errors() = %v, want %v", l.Errors(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestLexer_Errors_ReaderFailure(t *testing.T) {
+	readErr := errors.New("connection reset")
+	l := New(io.MultiReader(strings.NewReader("x + "), iotest.ErrReader(readErr)))
+
+	tokens := l.Tokenize()
+
+	expectedTokens := []token.Token{
+		{Type: token.Ident, Value: "x", Start: token.Pos{Offset: 0, Line: 1, Column: 1}, End: token.Pos{Offset: 1, Line: 1, Column: 2}},
+		{Type: token.Plus, Value: "+", Start: token.Pos{Offset: 2, Line: 1, Column: 3}, End: token.Pos{Offset: 3, Line: 1, Column: 4}},
+		{Type: token.Eof, Value: "", Start: token.Pos{Offset: 4, Line: 1, Column: 5}, End: token.Pos{Offset: 4, Line: 1, Column: 5}},
+	}
+	if !reflect.DeepEqual(tokens, expectedTokens) {
+		t.Errorf("Tokenize() = %v, want %v", tokens, expectedTokens)
+	}
+
+	errs := l.Errors()
+	if len(errs) != 1 {
+		t.Fatalf("Errors() has %d errors, want 1: %v", len(errs), errs)
+	}
+	if errs[0].Kind != IOFailure {
+		t.Errorf("Errors()[0].Kind = %v, want %v", errs[0].Kind, IOFailure)
+	}
+	if !errors.Is(errs[0], readErr) {
+		t.Errorf("Errors()[0] does not wrap the reader error: %v", errs[0])
+	}
+}
+
+func TestLexer_Warnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "Single script identifiers",
+			input:    "größe + имя",
+			expected: []string{},
+		},
+		{
+			name:     "Latin identifier containing a Cyrillic letter",
+			input:    "p\u0430ssword",
+			expected: []string{"identifier \"p\u0430ssword\" mixes Latin and Cyrillic characters, which may be confusable"},
+		},
+		{
+			name:     "Japanese identifiers",
+			input:    "読み込み + 検索キー + ユーザー名 + 名前_utf8",
+			expected: []string{},
+		},
+		{
+			name:     "Chinese and Korean identifiers",
+			input:    "ㄓ注音 + 사용자名",
+			expected: []string{},
+		},
+		{
+			name:     "Hangul mixed with Katakana",
+			input:    "사용자キー",
+			expected: []string{"identifier \"사용자キー\" mixes Hangul and Katakana characters, which may be confusable"},
+		},
+		{
+			name:     "Greek letter in a Han identifier",
+			input:    "名α",
+			expected: []string{"identifier \"名α\" mixes Han and Greek characters, which may be confusable"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(strings.NewReader(tt.input))
+			l.Tokenize()
+
+			if !reflect.DeepEqual(l.Warnings(), tt.expected) {
+				t.Errorf("Warnings() = %v, want %v", l.Warnings(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestLexer_Diagnostics(t *testing.T) {
+	l := New(strings.NewReader("p\u0430ss + # 'x"))
+	l.Tokenize()
+
+	var actual []string
+	for _, d := range l.Diagnostics() {
+		actual = append(actual, fmt.Sprintf("%s %v", d.Span, d))
+	}
+
+	expected := []string{
+		"1:1-1:5 1:1: warning[L0005]: identifier \"p\u0430ss\" mixes Latin and Cyrillic characters, which may be confusable",
+		"1:8-1:9 1:8: error[L0002]: invalid character '#'",
+		"1:10-1:12 1:10: error[L0001]: unterminated string",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Diagnostics() = %q, want %q", actual, expected)
+	}
+}
+
+func TestLexer_TokenSpans_SliceSource(t *testing.T) {
+	input := "let größe = 'a b' >= 0x1F;\n\"unterminated"
+	expected := []string{"let", "größe", "=", "'a b'", ">=", "0x1F", ";", "\"unterminated", ""}
+
+	l := New(strings.NewReader(input))
+	tokens := l.Tokenize()
+
+	if len(tokens) != len(expected) {
+		t.Fatalf("Tokenize() returned %d tokens, want %d: %v", len(tokens), len(expected), tokens)
+	}
+
+	for i, tok := range tokens {
+		actual := input[tok.Start.Offset:tok.End.Offset]
+		if actual != expected[i] {
+			t.Errorf("tokens[%d] spans %q, want %q", i, actual, expected[i])
+		}
+	}
+}
+
+func TestLexer_Comments(t *testing.T) {
+	l := New(strings.NewReader("x // a comment\n/ y"))
+
+	tokens := l.Tokenize()
+
+	expected := []token.Type{token.Ident, token.Divide, token.Ident, token.Eof}
+	if len(tokens) != len(expected) {
+		t.Fatalf("Tokenize() returned %d tokens, want %d: %v", len(tokens), len(expected), tokens)
+	}
+	for i, tok := range tokens {
+		if tok.Type != expected[i] {
+			t.Errorf("tokens[%d].Type = %s, want %s", i, token.GetStringFromTokenType(tok.Type), token.GetStringFromTokenType(expected[i]))
+		}
+	}
+}
+
+func TestLexer_Trivia_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "Empty input", input: ""},
+		{name: "Only whitespace", input: "  \n\t \r\n"},
+		{name: "Statements with comments", input: "// header\nlet x = 5; // five\n\n  let y='str' ;\n"},
+		{name: "Unnormalised identifier", input: "café + 1_000"},
+		{name: "Illegal input", input: "x & # \"unterminated"},
+		{name: "Comment at end of input", input: "x // no newline"},
+		{name: "Embedded NUL", input: "a\x00b // c\x00d\n'e\x00f'"},
+		{name: "Invalid UTF-8", input: "a \xff\xfe b // \xc3\n\x80"},
+		{name: "Trailing NUL", input: "x\x00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(strings.NewReader(tt.input), WithTrivia())
+
+			var builder strings.Builder
+			for _, tok := range l.Tokenize() {
+				builder.WriteString(tok.FullText())
+			}
+
+			if builder.String() != tt.input {
+				t.Errorf("reconstructed %q, want %q", builder.String(), tt.input)
+			}
+		})
+		t.Run(tt.name+" from bytes", func(t *testing.T) {
+			l := NewFromBytes([]byte(tt.input), WithTrivia())
+
+			var builder strings.Builder
+			for _, tok := range l.Tokenize() {
+				builder.WriteString(tok.FullText())
+			}
+
+			if builder.String() != tt.input {
+				t.Errorf("reconstructed %q, want %q", builder.String(), tt.input)
+			}
+		})
+	}
+}
+
+func TestLexer_Trivia_Attachment(t *testing.T) {
+	l := New(strings.NewReader("a // one\n  b"), WithTrivia())
+
+	tokens := l.Tokenize()
+
+	expected := []token.Token{
+		{
+			Type:  token.Ident,
+			Value: "a",
+			Start: token.Pos{Offset: 0, Line: 1, Column: 1},
+			End:   token.Pos{Offset: 1, Line: 1, Column: 2},
+			Source: &token.Source{
+				Raw:     "a",
+				Leading: nil,
+				Trailing: []token.Trivia{
+					{Kind: token.Whitespace, Text: " ", Start: token.Pos{Offset: 1, Line: 1, Column: 2}, End: token.Pos{Offset: 2, Line: 1, Column: 3}},
+					{Kind: token.Comment, Text: "// one", Start: token.Pos{Offset: 2, Line: 1, Column: 3}, End: token.Pos{Offset: 8, Line: 1, Column: 9}},
+					{Kind: token.Newline, Text: "\n", Start: token.Pos{Offset: 8, Line: 1, Column: 9}, End: token.Pos{Offset: 9, Line: 2, Column: 1}},
+				},
+			},
+		},
+		{
+			Type:  token.Ident,
+			Value: "b",
+			Start: token.Pos{Offset: 11, Line: 2, Column: 3},
+			End:   token.Pos{Offset: 12, Line: 2, Column: 4},
+			Source: &token.Source{
+				Raw: "b",
+				Leading: []token.Trivia{
+					{Kind: token.Whitespace, Text: "  ", Start: token.Pos{Offset: 9, Line: 2, Column: 1}, End: token.Pos{Offset: 11, Line: 2, Column: 3}},
+				},
+				Trailing: nil,
+			},
+		},
+		{
+			Type:   token.Eof,
+			Value:  "",
+			Start:  token.Pos{Offset: 12, Line: 2, Column: 4},
+			End:    token.Pos{Offset: 12, Line: 2, Column: 4},
+			Source: &token.Source{},
+		},
+	}
+
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("Tokenize() = %#v, want %#v", tokens, expected)
+	}
+}
+
+func TestLexer_LargeWhitespaceRuns(t *testing.T) {
+	const n = 1 << 20
+
+	tests := []struct {
+		name     string
+		input    string
+		expected token.Pos
+	}{
+		{
+			name:     "Long run of spaces",
+			input:    strings.Repeat(" ", n) + "x",
+			expected: token.Pos{Offset: n, Line: 1, Column: n + 1},
+		},
+		{
+			name:     "Many blank lines",
+			input:    strings.Repeat("\n", n) + "x",
+			expected: token.Pos{Offset: n, Line: n + 1, Column: 1},
+		},
+		{
+			name:     "Many comment lines",
+			input:    strings.Repeat("//\n", n) + "x",
+			expected: token.Pos{Offset: 3 * n, Line: n + 1, Column: 1},
+		},
+		{
+			name:     "Very long line",
+			input:    strings.Repeat("a + ", n/16) + "x",
+			expected: token.Pos{Offset: n / 4, Line: 1, Column: n/4 + 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(strings.NewReader(tt.input))
+
+			tokens := l.Tokenize()
+
+			last := tokens[len(tokens)-2]
+			if last.Value != "x" || last.Start != tt.expected {
+				t.Errorf("last token = %v at %v, want x at %v", last, last.Start, tt.expected)
+			}
+		})
+	}
+}
+
+func BenchmarkLexer_LargeWhitespaceRun(b *testing.B) {
+	input := strings.Repeat(" \t\n", 1<<20) + "x"
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input)))
+	for i := 0; i < b.N; i++ {
+		l := New(strings.NewReader(input))
+		l.Tokenize()
+	}
+}
+
+func BenchmarkLexer_LongLine(b *testing.B) {
+	input := strings.Repeat("abc + 123 * ", 1<<16)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input)))
+	for i := 0; i < b.N; i++ {
+		l := New(strings.NewReader(input))
+		l.Tokenize()
+	}
+}
+
+func TestNewFromBytes_ValuesShareInput(t *testing.T) {
+	input := "let total = 0xFF + 'text';"
+	l := NewFromBytes([]byte(input))
+
+	// Every value points into the lexer's copy of the input.
+	start := uintptr(unsafe.Pointer(unsafe.StringData(l.input)))
+	end := start + uintptr(len(l.input))
+	for tok := range l.Tokens() {
+		if tok.Value == "" {
+			continue
+		}
+		data := uintptr(unsafe.Pointer(unsafe.StringData(tok.Value)))
+		if data < start || data+uintptr(len(tok.Value)) > end {
+			t.Errorf("value of %v does not share the input", tok)
+		}
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		l = NewFromBytes([]byte(input))
+		for l.NextToken().Type != token.Eof {
+		}
+	})
+
+	// One allocation each for the lexer, its error and warning slices and the
+	// copy of the input.
+	if allocs > 4 {
+		t.Errorf("lexing in-memory input made %v allocations, want at most 4", allocs)
+	}
+}
+
+// generatedScript builds a script resembling machine-generated source.
+func generatedScript(lines int) []byte {
+	var builder strings.Builder
+	for i := 0; i < lines; i++ {
+		builder.WriteString("let value_with_long_name = (first + 0x1F) * 1_000 / 'a string'; // note\n")
+	}
+	return []byte(builder.String())
+}
+
+func BenchmarkLexer_Reader(b *testing.B) {
+	src := generatedScript(50_000)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(src)))
+	for i := 0; i < b.N; i++ {
+		l := New(bytes.NewReader(src))
+		for l.NextToken().Type != token.Eof {
+		}
+	}
+}
+
+func BenchmarkLexer_Bytes(b *testing.B) {
+	src := generatedScript(50_000)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(src)))
+	for i := 0; i < b.N; i++ {
+		l := NewFromBytes(src)
+		for l.NextToken().Type != token.Eof {
+		}
+	}
+}
+
+func TestLexer_Tokens_Streaming(t *testing.T) {
+	r, w := io.Pipe()
+
+	// The second line is only written once the first line's tokens have been
+	// read, so the lexer must not wait for more input than it needs.
+	lines := []string{"let x = 1;\n", "x + 2;\n"}
+	go func() {
+		_, _ = w.Write([]byte(lines[0]))
+	}()
+	l := New(r)
+
+	var values []string
+	for tok := range l.Tokens() {
+		values = append(values, tok.Value)
+		if tok.Value == ";" && len(values) == 5 {
+			go func() {
+				_, _ = w.Write([]byte(lines[1]))
+				_ = w.Close()
+			}()
+		}
+	}
+
+	expected := []string{"let", "x", "=", "1", ";", "x", "+", "2", ";", ""}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Tokens() yielded %q, want %q", values, expected)
+	}
+}
+
+func TestLexer_Tokens_StopEarly(t *testing.T) {
+	l := New(strings.NewReader("a b c d"))
+
+	var values []string
+	for tok := range l.Tokens() {
+		values = append(values, tok.Value)
+		if len(values) == 2 {
+			break
+		}
+	}
+
+	if next := l.NextToken(); next.Value != "c" {
+		t.Errorf("NextToken() after stopping = %v, want c", next)
+	}
+}
+
+func TestLexer_Operators(t *testing.T) {
+	input := "=> ? ?. ?? ... ** & | ^ ~ << >> && || == >= <= != .. a?.5:1"
+
+	expected := []struct {
+		t     token.Type
+		value string
+	}{
+		{token.Arrow, "=>"},
+		{token.Question, "?"},
+		{token.OptionalChain, "?."},
+		{token.NullishCoalescing, "??"},
+		{token.Ellipsis, "..."},
+		{token.Power, "**"},
+		{token.BitAnd, "&"},
+		{token.BitOr, "|"},
+		{token.BitXor, "^"},
+		{token.BitNot, "~"},
+		{token.ShiftLeft, "<<"},
+		{token.ShiftRight, ">>"},
+		{token.And, "&&"},
+		{token.Or, "||"},
+		{token.Equal, "=="},
+		{token.GreaterThanOrEqual, ">="},
+		{token.LessThanOrEqual, "<="},
+		{token.NotEqual, "!="},
+		{token.FullStop, "."},
+		{token.FullStop, "."},
+		{token.Ident, "a"},
+		{token.Question, "?"},
+		{token.Number, ".5"},
+		{token.Colon, ":"},
+		{token.Number, "1"},
+		{token.Eof, ""},
+	}
+
+	tokens := New(strings.NewReader(input)).Tokenize()
+	if len(tokens) != len(expected) {
+		t.Fatalf("Tokenize() returned %d tokens, want %d: %v", len(tokens), len(expected), tokens)
+	}
+
+	for i, tok := range tokens {
+		if tok.Type != expected[i].t || tok.Value != expected[i].value {
+			t.Errorf("tokens[%d] = %v, want %s %q", i, tok, token.GetStringFromTokenType(expected[i].t), expected[i].value)
+		}
+	}
+}
+
+func TestLexer_DefineOperator(t *testing.T) {
+	input := "a =~ b == c #> d # e ~ matches"
+
+	expected := []struct {
+		t     token.Type
+		value string
+	}{
+		{token.Ident, "a"},
+		{token.Operator, "=~"},
+		{token.Ident, "b"},
+		{token.Equal, "=="},
+		{token.Ident, "c"},
+		{token.Operator, "#>"},
+		{token.Ident, "d"},
+		{token.Operator, "#"},
+		{token.Ident, "e"},
+		{token.BitNot, "~"},
+		{token.Ident, "matches"},
+		{token.Eof, ""},
+	}
+
+	l := New(strings.NewReader(input), WithOperators("=~", "#", "#>", "==", "matches"))
+	tokens := l.Tokenize()
+	if len(tokens) != len(expected) {
+		t.Fatalf("Tokenize() returned %d tokens, want %d: %v", len(tokens), len(expected), tokens)
+	}
+
+	for i, tok := range tokens {
+		if tok.Type != expected[i].t || tok.Value != expected[i].value {
+			t.Errorf("tokens[%d] = %v, want %s %q", i, tok, token.GetStringFromTokenType(expected[i].t), expected[i].value)
+		}
+	}
+
+	// Other lexers are unaffected.
+	if tok := New(strings.NewReader("=~")).NextToken(); tok.Type != token.Assign {
+		t.Errorf("expected = in a lexer without definitions, got %v", tok)
+	}
+}
+
+func TestLexer_IsOperator(t *testing.T) {
+	l := New(strings.NewReader("a =~ !b + 1"), WithOperators("=~"))
+
+	expected := []bool{false, true, true, false, true, false, false}
+	for i, tok := range l.Tokenize() {
+		if actual := l.IsOperator(tok); actual != expected[i] {
+			t.Errorf("IsOperator(%v) = %t, want %t", tok, actual, expected[i])
+		}
+	}
+}
+
+func TestLexer_DefineOperator_PanicsOnInvalidSpelling(t *testing.T) {
+	for _, spelling := range []string{"", "+1", "a+", "< >", "'"} {
+		t.Run(spelling, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected DefineOperator(%q) to panic", spelling)
+				}
+			}()
+			New(strings.NewReader("")).DefineOperator(spelling)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package lexer
+
+import (
+	"lang/lexer/token"
+	"slices"
+)
+
+// Mark is a saved lexer position, created by Lexer.Mark.
+type Mark struct {
+	ch       rune
+	size     int
+	offset   int
+	line     int
+	col      int
+	start    token.Pos
+	failed   bool
+	errors   int
+	warnings int
+}
+
+// Mark saves the lexer's position so that a caller can lex speculatively and
+// then back out with Reset. The input from the mark onwards stays buffered
+// until the mark is released, so marks should be released once they are no
+// longer needed.
+func (l *Lexer) Mark() Mark {
+	l.marks = append(l.marks, l.offset)
+
+	return Mark{
+		ch:       l.ch,
+		size:     l.size,
+		offset:   l.offset,
+		line:     l.line,
+		col:      l.col,
+		start:    l.start,
+		failed:   l.failed,
+		errors:   len(l.errors),
+		warnings: len(l.warnings),
+	}
+}
+
+// Reset returns the lexer to the given mark. Errors and warnings reported
+// since the mark are discarded, as they will be reported again when the input
+// is re-lexed. The mark remains valid and may be reset to again.
+func (l *Lexer) Reset(m Mark) {
+	l.ch = m.ch
+	l.size = m.size
+	l.offset = m.offset
+	l.line = m.line
+	l.col = m.col
+	l.start = m.start
+	l.failed = m.failed
+	l.errors = l.errors[:m.errors]
+	l.warnings = l.warnings[:m.warnings]
+}
+
+// Release discards the mark, allowing the input it held to be freed.
+func (l *Lexer) Release(m Mark) {
+	if i := slices.Index(l.marks, m.offset); i >= 0 {
+		l.marks = slices.Delete(l.marks, i, i+1)
+	}
+}
@@ -0,0 +1,81 @@
+package lexer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestLexer_MarkReset(t *testing.T) {
+	input := "(a, b) => a + #"
+
+	constructors := map[string]func() *Lexer{
+		"reader": func() *Lexer { return New(iotest.OneByteReader(strings.NewReader(input))) },
+		"bytes":  func() *Lexer { return NewFromBytes([]byte(input)) },
+	}
+
+	for name, newLexer := range constructors {
+		t.Run(name, func(t *testing.T) {
+			l := newLexer()
+			l.NextToken()
+
+			m := l.Mark()
+			first := l.Tokenize()
+			if len(l.Errors()) != 1 {
+				t.Fatalf("Errors() = %v, want the invalid character", l.Errors())
+			}
+
+			l.Reset(m)
+			if len(l.Errors()) != 0 {
+				t.Errorf("Errors() after Reset = %v, want none", l.Errors())
+			}
+
+			second := l.Tokenize()
+			if !reflect.DeepEqual(first, second) {
+				t.Errorf("tokens after Reset = %v, want %v", second, first)
+			}
+			if len(l.Errors()) != 1 {
+				t.Errorf("Errors() after re-lexing = %v, want the invalid character", l.Errors())
+			}
+
+			l.Release(m)
+		})
+	}
+}
+
+func TestLexer_PeekChar(t *testing.T) {
+	l := New(iotest.OneByteReader(strings.NewReader("a...é")))
+
+	expected := []rune{'.', '.', '.', 'é', 0, 0}
+	for i, r := range expected {
+		if actual := l.peekChar(i + 1); actual != r {
+			t.Errorf("peekChar(%d) = %q, want %q", i+1, actual, r)
+		}
+	}
+
+	if l.ch != 'a' {
+		t.Errorf("peeking consumed input: current character is %q", l.ch)
+	}
+}
+
+func TestLexer_MarkRetainsBufferedInput(t *testing.T) {
+	input := strings.Repeat("abc ", 1<<16)
+
+	l := New(strings.NewReader(input))
+	l.Tokenize()
+	if len(l.buf) >= len(input) {
+		t.Errorf("buffered %d bytes without a mark, want less than %d", len(l.buf), len(input))
+	}
+
+	l = New(strings.NewReader(input))
+	m := l.Mark()
+	l.Tokenize()
+	if len(l.buf) < len(input) {
+		t.Errorf("buffered %d bytes while marked, want the whole input of %d bytes", len(l.buf), len(input))
+	}
+	l.Release(m)
+	if len(l.marks) != 0 {
+		t.Errorf("marks = %v after Release, want none", l.marks)
+	}
+}
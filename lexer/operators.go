@@ -0,0 +1,145 @@
+package lexer
+
+import (
+	"fmt"
+	"lang/lexer/token"
+	"maps"
+	"slices"
+	"unicode/utf8"
+)
+
+// operator is one spelling of a punctuation token.
+type operator struct {
+	spelling string
+	t        token.Type
+}
+
+// operators lists the punctuation tokens by their first character. Longer
+// spellings come first so that the longest match wins.
+var operators = map[rune][]operator{
+	'=': {{"==", token.Equal}, {"=>", token.Arrow}, {"=", token.Assign}},
+	'+': {{"+", token.Plus}},
+	'-': {{"-", token.Minus}},
+	'*': {{"**", token.Power}, {"*", token.Multiply}},
+	'/': {{"/", token.Divide}},
+	'%': {{"%", token.Percent}},
+	',': {{",", token.Comma}},
+	'.': {{"...", token.Ellipsis}, {".", token.FullStop}},
+	';': {{";", token.Semicolon}},
+	':': {{":", token.Colon}},
+	'(': {{"(", token.LParen}},
+	')': {{")", token.RParen}},
+	'{': {{"{", token.LBrace}},
+	'}': {{"}", token.RBrace}},
+	'[': {{"[", token.LBracket}},
+	']': {{"]", token.RBracket}},
+	'>': {{">=", token.GreaterThanOrEqual}, {">>", token.ShiftRight}, {">", token.GreaterThan}},
+	'<': {{"<=", token.LessThanOrEqual}, {"<<", token.ShiftLeft}, {"<", token.LessThan}},
+	'!': {{"!=", token.NotEqual}, {"!", token.Not}},
+	'&': {{"&&", token.And}, {"&", token.BitAnd}},
+	'|': {{"||", token.Or}, {"|", token.BitOr}},
+	'^': {{"^", token.BitXor}},
+	'~': {{"~", token.BitNot}},
+	'?': {{"??", token.NullishCoalescing}, {"?.", token.OptionalChain}, {"?", token.Question}},
+}
+
+// DefineOperator adds an operator spelling to those the lexer recognises,
+// which is then lexed as an Operator token whose value is the spelling. As
+// with the built-in operators the longest match wins, so defining =~ lexes
+// a =~ b as a single operator rather than = followed by ~. Spellings that are
+// already recognised keep their token type.
+//
+// Spellings that are valid identifiers, such as matches, already lex as
+// identifiers or keywords and need no definition, so they are ignored. It
+// panics if spelling is empty or mixes identifier characters with
+// punctuation, or contains digits, quotes or whitespace.
+func (l *Lexer) DefineOperator(spelling string) {
+	if isIdentifier(spelling) {
+		return
+	}
+	if spelling == "" {
+		panic("lexer: operator spelling is empty")
+	}
+	for _, ch := range spelling {
+		if isIdentifierContinue(ch) || isWhitespace(ch) || ch == '"' || ch == '\'' || ch == 0 {
+			panic(fmt.Sprintf("lexer: %q is not a valid operator spelling", spelling))
+		}
+	}
+
+	first, _ := utf8.DecodeRuneInString(spelling)
+	for _, op := range l.operators[first] {
+		if op.spelling == spelling {
+			return
+		}
+	}
+
+	if !l.ownsOperators {
+		l.operators = maps.Clone(l.operators)
+		l.ownsOperators = true
+	}
+
+	candidates := append(slices.Clone(l.operators[first]), operator{spelling, token.Operator})
+	slices.SortStableFunc(candidates, func(a, b operator) int {
+		return len(b.spelling) - len(a.spelling)
+	})
+	l.operators[first] = candidates
+}
+
+// IsOperator reports whether t was lexed from one of the operator spellings
+// the lexer recognises, whether built in, such as + or !, or defined with
+// DefineOperator.
+func (l *Lexer) IsOperator(t token.Token) bool {
+	first, _ := utf8.DecodeRuneInString(t.Value)
+	for _, op := range l.operators[first] {
+		if op.spelling == t.Value && op.t == t.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// isIdentifier reports whether s is a single identifier.
+func isIdentifier(s string) bool {
+	for i, ch := range s {
+		if i == 0 && !isIdentifierStart(ch) || i > 0 && !isIdentifierContinue(ch) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// readOperator reads the longest of the candidate operators that matches the
+// input.
+func (l *Lexer) readOperator(candidates []operator) token.Token {
+	for _, op := range candidates {
+		if !l.matches(op.spelling) {
+			continue
+		}
+
+		// As in JavaScript, "a?.5:b" is a conditional rather than an optional
+		// chain.
+		if op.t == token.OptionalChain && isDecimalDigit(l.peekChar(2)) {
+			continue
+		}
+
+		for range op.spelling {
+			l.readNextChar()
+		}
+		return l.newToken(op.t, l.text())
+	}
+
+	return l.handleIllegalRune()
+}
+
+// matches reports whether the input from the current character onwards
+// begins with s.
+func (l *Lexer) matches(s string) bool {
+	i := 0
+	for _, r := range s {
+		if i == 0 && l.ch != r || i > 0 && l.peekChar(i) != r {
+			return false
+		}
+		i++
+	}
+	return true
+}
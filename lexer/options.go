@@ -0,0 +1,91 @@
+package lexer
+
+import "lang/lexer/token"
+
+// ColumnUnit selects what a column number counts.
+type ColumnUnit int
+
+const (
+	// Runes counts Unicode code points. This is the default.
+	Runes ColumnUnit = iota
+	// Bytes counts UTF-8 bytes.
+	Bytes
+	// UTF16 counts UTF-16 code units, as expected by LSP clients.
+	UTF16
+)
+
+// Option configures a Lexer.
+type Option func(*Lexer)
+
+// WithColumnUnit reports columns in the given unit.
+func WithColumnUnit(unit ColumnUnit) Option {
+	return func(l *Lexer) {
+		l.columnUnit = unit
+	}
+}
+
+// WithTabWidth expands tabs to the next multiple of width when computing
+// columns, so that columns match what an editor displays. A width of zero or
+// less counts a tab like any other character.
+func WithTabWidth(width int) Option {
+	return func(l *Lexer) {
+		l.tabWidth = width
+	}
+}
+
+// WithFilename records a file with the given name in every position, so that
+// diagnostics say where in a multi-file project they come from.
+func WithFilename(name string) Option {
+	return func(l *Lexer) {
+		l.file = &token.File{Name: name}
+	}
+}
+
+// WithOperators defines additional operator spellings, as DefineOperator
+// does.
+func WithOperators(spellings ...string) Option {
+	return func(l *Lexer) {
+		for _, spelling := range spellings {
+			l.DefineOperator(spelling)
+		}
+	}
+}
+
+// WithTrivia attaches whitespace, newlines and comments to tokens as trivia,
+// and records each token's raw source text, so that the input can be
+// reconstructed exactly from the token stream.
+func WithTrivia() Option {
+	return func(l *Lexer) {
+		l.trivia = true
+	}
+}
+
+// WithDialect reserves only the keywords of the given dialect. The default is
+// token.Full.
+func WithDialect(d *token.Dialect) Option {
+	return func(l *Lexer) {
+		l.dialect = d
+	}
+}
+
+// advanceColumn moves col past the character ch, which is size bytes long.
+func (l *Lexer) advanceColumn(ch rune, size int) {
+	if ch == '\t' && l.tabWidth > 0 {
+		l.col = ((l.col-1)/l.tabWidth+1)*l.tabWidth + 1
+		return
+	}
+
+	switch l.columnUnit {
+	case Bytes:
+		l.col += size
+	case UTF16:
+		// Runes outside the Basic Multilingual Plane need a surrogate pair.
+		if ch >= 0x10000 {
+			l.col += 2
+		} else {
+			l.col++
+		}
+	default:
+		l.col++
+	}
+}
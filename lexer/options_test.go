@@ -0,0 +1,74 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLexer_ColumnOptions(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		opts           []Option
+		expectedColumn int
+	}{
+		{
+			name:           "Runes by default",
+			input:          "'é𝄞' x",
+			expectedColumn: 6,
+		},
+		{
+			name:           "Bytes",
+			input:          "'é𝄞' x",
+			opts:           []Option{WithColumnUnit(Bytes)},
+			expectedColumn: 10,
+		},
+		{
+			name:           "UTF-16 code units",
+			input:          "'é𝄞' x",
+			opts:           []Option{WithColumnUnit(UTF16)},
+			expectedColumn: 7,
+		},
+		{
+			name:           "Tabs not expanded by default",
+			input:          "\tx",
+			expectedColumn: 2,
+		},
+		{
+			name:           "Leading tab expanded",
+			input:          "\tx",
+			opts:           []Option{WithTabWidth(4)},
+			expectedColumn: 5,
+		},
+		{
+			name:           "Tab expanded to next tab stop",
+			input:          "ab\tx",
+			opts:           []Option{WithTabWidth(4)},
+			expectedColumn: 5,
+		},
+		{
+			name:           "Tab at a tab stop",
+			input:          "abcd\tx",
+			opts:           []Option{WithTabWidth(4)},
+			expectedColumn: 9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(strings.NewReader(tt.input), tt.opts...)
+			tokens := l.Tokenize()
+
+			last := tokens[len(tokens)-2]
+			if last.Value != "x" {
+				t.Fatalf("expected last token to be x, got %v", last)
+			}
+			if last.Start.Line != 1 {
+				t.Errorf("x.Start.Line = %d, want 1", last.Start.Line)
+			}
+			if last.Start.Column != tt.expectedColumn {
+				t.Errorf("x.Start.Column = %d, want %d", last.Start.Column, tt.expectedColumn)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package lexer
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// readChunkSize is how many bytes are requested from the reader at a time.
+const readChunkSize = 4096
+
+// readNextChar reads the next character from the input string.
+func (l *Lexer) readNextChar() {
+	if l.size > 0 {
+		if l.ch == '\n' {
+			l.line++
+			l.col = 1
+		} else {
+			l.advanceColumn(l.ch, l.size)
+		}
+	}
+	l.offset += l.size
+
+	l.ch, l.size = l.decodeAt(l.offset)
+}
+
+// atEnd reports whether the whole input has been read. The end is told from
+// the size of the current character rather than its value, so a NUL in the
+// input is lexed as an invalid character instead of ending the input.
+func (l *Lexer) atEnd() bool {
+	return l.size == 0
+}
+
+// peekChar returns the character n positions after the current one without
+// consuming anything, so peekChar(1) is the next character. It returns 0 past
+// the end of the input.
+func (l *Lexer) peekChar(n int) rune {
+	offset := l.offset + l.size
+	for {
+		r, size := l.decodeAt(offset)
+		if size == 0 {
+			return 0
+		}
+		n--
+		if n == 0 {
+			return r
+		}
+		offset += size
+	}
+}
+
+// decodeAt decodes the character at the given byte offset, returning a size
+// of zero past the end of the input.
+func (l *Lexer) decodeAt(offset int) (rune, int) {
+	if l.reader == nil {
+		if offset >= len(l.input) {
+			return 0, 0
+		}
+		return utf8.DecodeRuneInString(l.input[offset:])
+	}
+
+	// Read only as much as is needed to decode one character, so that a
+	// streaming reader is not blocked on waiting for further input.
+	l.fill(offset + 1)
+	i := offset - l.bufOffset
+	for i < len(l.buf) && !utf8.FullRune(l.buf[i:]) && l.readErr == nil {
+		l.fill(l.bufOffset + len(l.buf) + 1)
+		i = offset - l.bufOffset
+	}
+
+	if i >= len(l.buf) {
+		if l.readErr != io.EOF && !l.failed {
+			l.addIOError(l.readErr)
+		}
+		return 0, 0
+	}
+
+	return utf8.DecodeRune(l.buf[i:])
+}
+
+// fill reads from the reader until the input up to the given byte offset is
+// buffered or the reader is exhausted.
+func (l *Lexer) fill(offset int) {
+	for l.readErr == nil && l.bufOffset+len(l.buf) < offset {
+		l.compact()
+
+		var chunk [readChunkSize]byte
+		n, err := l.reader.Read(chunk[:])
+		l.buf = append(l.buf, chunk[:n]...)
+		if err != nil {
+			l.readErr = err
+		}
+	}
+}
+
+// compact discards buffered input that can no longer be needed: everything
+// before the start of the current token and the oldest outstanding mark.
+func (l *Lexer) compact() {
+	keep := min(l.start.Offset, l.offset)
+	for _, m := range l.marks {
+		keep = min(keep, m)
+	}
+
+	discard := keep - l.bufOffset
+	if discard < len(l.buf)/2 {
+		return
+	}
+
+	l.buf = l.buf[:copy(l.buf, l.buf[discard:])]
+	l.bufOffset = keep
+}
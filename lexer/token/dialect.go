@@ -0,0 +1,102 @@
+package token
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Dialect selects which keywords of the language are reserved. Words that are
+// not reserved in a dialect lex as identifiers, so scripts written for a
+// smaller dialect keep working when they use later keywords as names.
+//
+// Contextual keywords are lexed as keywords but may still be used as
+// identifiers wherever a keyword would be ambiguous.
+type Dialect struct {
+	keywords   map[string]Type
+	contextual map[Type]bool
+}
+
+// NewDialect creates a dialect reserving the given keywords, of which those
+// also listed in contextual are contextual. It panics if a word is not a
+// keyword of the full language.
+func NewDialect(keywords []string, contextual []string) *Dialect {
+	d := &Dialect{
+		keywords:   make(map[string]Type, len(keywords)),
+		contextual: make(map[Type]bool, len(contextual)),
+	}
+
+	for _, kw := range keywords {
+		t, exists := keywordsToTypes[kw]
+		if !exists {
+			panic(fmt.Sprintf("token: %q is not a keyword", kw))
+		}
+		d.keywords[kw] = t
+	}
+
+	for _, kw := range contextual {
+		t, exists := d.keywords[kw]
+		if !exists {
+			panic(fmt.Sprintf("token: contextual keyword %q is not reserved in the dialect", kw))
+		}
+		d.contextual[t] = true
+	}
+
+	return d
+}
+
+var (
+	// Minimal is an expression-only dialect that reserves just the boolean
+	// literals.
+	Minimal = NewDialect([]string{"true", "false"}, nil)
+
+	// Standard reserves the statement and control flow keywords, but none of
+	// the class-based ones.
+	Standard = NewDialect([]string{
+		"if", "else", "while", "do", "for", "function", "define", "const",
+		"let", "include", "in", "break", "continue", "catch", "try", "switch",
+		"case", "default", "enum", "export", "throw", "return", "finally",
+		"true", "false",
+	}, nil)
+
+	// Full reserves every keyword of the language. Modifiers and the words
+	// used in class declarations are contextual.
+	Full = NewDialect(allKeywords(), []string{
+		"extends", "implements", "private", "protected", "public", "static", "abstract",
+	})
+)
+
+func allKeywords() []string {
+	keywords := make([]string, 0, len(keywordsToTypes))
+	for kw := range keywordsToTypes {
+		keywords = append(keywords, kw)
+	}
+	return keywords
+}
+
+// KeywordType returns the type of kw if it is reserved in the dialect, or
+// Ident otherwise.
+func (d *Dialect) KeywordType(kw string) Type {
+	t, exists := d.keywords[kw]
+
+	if exists {
+		return t
+	}
+
+	return Ident
+}
+
+// Keywords returns the words reserved in the dialect, in sorted order.
+func (d *Dialect) Keywords() []string {
+	keywords := make([]string, 0, len(d.keywords))
+	for kw := range d.keywords {
+		keywords = append(keywords, kw)
+	}
+	slices.Sort(keywords)
+	return keywords
+}
+
+// IsContextual reports whether tokens of type t may also be used as
+// identifiers.
+func (d *Dialect) IsContextual(t Type) bool {
+	return d.contextual[t]
+}
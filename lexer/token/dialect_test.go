@@ -0,0 +1,61 @@
+package token
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDialect_KeywordType(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  *Dialect
+		word     string
+		expected Type
+	}{
+		{name: "Full reserves class", dialect: Full, word: "class", expected: Class},
+		{name: "Standard does not reserve class", dialect: Standard, word: "class", expected: Ident},
+		{name: "Standard reserves let", dialect: Standard, word: "let", expected: Let},
+		{name: "Minimal does not reserve let", dialect: Minimal, word: "let", expected: Ident},
+		{name: "Minimal reserves true", dialect: Minimal, word: "true", expected: True},
+		{name: "Identifier in every dialect", dialect: Full, word: "total", expected: Ident},
+		{name: "Custom dialect", dialect: NewDialect([]string{"let", "class"}, nil), word: "class", expected: Class},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.dialect.KeywordType(tt.word)
+			if actual != tt.expected {
+				t.Errorf("Expected: %v, Actual: %v", GetStringFromTokenType(tt.expected), GetStringFromTokenType(actual))
+			}
+		})
+	}
+}
+
+func TestDialect_IsContextual(t *testing.T) {
+	if !Full.IsContextual(Static) {
+		t.Errorf("Expected static to be contextual in the full dialect")
+	}
+	if Full.IsContextual(Class) {
+		t.Errorf("Expected class not to be contextual in the full dialect")
+	}
+}
+
+func TestDialect_Keywords(t *testing.T) {
+	d := NewDialect([]string{"let", "return", "if"}, nil)
+	if actual, expected := d.Keywords(), []string{"if", "let", "return"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if actual, expected := Minimal.Keywords(), []string{"false", "true"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestNewDialect_PanicsOnUnknownKeyword(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected NewDialect to panic for a word that is not a keyword")
+		}
+	}()
+
+	NewDialect([]string{"lett"}, nil)
+}
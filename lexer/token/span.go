@@ -0,0 +1,70 @@
+package token
+
+import "fmt"
+
+// File names an input. Positions refer to it rather than holding its name,
+// so that naming the input does not enlarge every token.
+type File struct {
+	Name string
+}
+
+// Pos is a position in the source. Offset is a byte offset into the input,
+// while Line and Column are intended for display. File is nil unless the
+// input was read from a named file.
+type Pos struct {
+	File   *File
+	Offset int
+	Line   int
+	Column int
+}
+
+// Filename returns the name of the file the position is in, or "" if it has
+// none.
+func (p Pos) Filename() string {
+	if p.File == nil {
+		return ""
+	}
+	return p.File.Name
+}
+
+// String formats the position as line:column, prefixed by the filename if
+// there is one, as in config.lang:12:4.
+func (p Pos) String() string {
+	if name := p.Filename(); name != "" {
+		return fmt.Sprintf("%s:%d:%d", name, p.Line, p.Column)
+	}
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Span is the half-open range of source between Start and End.
+type Span struct {
+	Start Pos
+	End   Pos
+}
+
+// Contains reports whether the byte offset lies within the span.
+func (s Span) Contains(offset int) bool {
+	return s.Start.Offset <= offset && offset < s.End.Offset
+}
+
+// Union returns the smallest span covering both s and other.
+func (s Span) Union(other Span) Span {
+	u := s
+	if other.Start.Offset < u.Start.Offset {
+		u.Start = other.Start
+	}
+	if other.End.Offset > u.End.Offset {
+		u.End = other.End
+	}
+	return u
+}
+
+// String formats the span as start-end, naming the file only once, as in
+// config.lang:1:5-1:9.
+func (s Span) String() string {
+	end := s.End
+	if end.Filename() == s.Start.Filename() {
+		end.File = nil
+	}
+	return fmt.Sprintf("%s-%s", s.Start, end)
+}
@@ -0,0 +1,82 @@
+package token
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSpan_Contains(t *testing.T) {
+	span := Span{
+		Start: Pos{Offset: 4, Line: 0, Column: 4},
+		End:   Pos{Offset: 8, Line: 0, Column: 8},
+	}
+
+	tests := []struct {
+		name     string
+		offset   int
+		expected bool
+	}{
+		{name: "Before start", offset: 3, expected: false},
+		{name: "At start", offset: 4, expected: true},
+		{name: "Inside", offset: 6, expected: true},
+		{name: "At end", offset: 8, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := span.Contains(tt.offset)
+			if actual != tt.expected {
+				t.Errorf("Expected: %v, Actual: %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSpan_Union(t *testing.T) {
+	first := Span{
+		Start: Pos{Offset: 2, Line: 0, Column: 2},
+		End:   Pos{Offset: 5, Line: 0, Column: 5},
+	}
+	second := Span{
+		Start: Pos{Offset: 9, Line: 1, Column: 1},
+		End:   Pos{Offset: 12, Line: 1, Column: 4},
+	}
+	expected := Span{
+		Start: Pos{Offset: 2, Line: 0, Column: 2},
+		End:   Pos{Offset: 12, Line: 1, Column: 4},
+	}
+
+	if actual := first.Union(second); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if actual := second.Union(first); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestSpan_String(t *testing.T) {
+	span := Span{
+		Start: Pos{Offset: 2, Line: 0, Column: 2},
+		End:   Pos{Offset: 12, Line: 1, Column: 4},
+	}
+	expected := "0:2-1:4"
+
+	if actual := span.String(); actual != expected {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestSpan_String_Filename(t *testing.T) {
+	file := &File{Name: "config.lang"}
+	span := Span{
+		Start: Pos{File: file, Offset: 2, Line: 12, Column: 4},
+		End:   Pos{File: file, Offset: 6, Line: 12, Column: 8},
+	}
+
+	if actual, expected := span.Start.String(), "config.lang:12:4"; actual != expected {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+	if actual, expected := span.String(), "config.lang:12:4-12:8"; actual != expected {
+		t.Errorf("Expected: %v, Actual: %v", expected, actual)
+	}
+}
@@ -6,24 +6,36 @@ import (
 
 type Type int
 
+// Token is a lexical token together with the exact range of source it was
+// read from. End is exclusive, so input[Start.Offset:End.Offset] recovers the
+// source text of the token.
+//
+// Source is only set by a lexer in trivia mode, so that tokens stay small
+// otherwise.
 type Token struct {
 	Type   Type
 	Value  string
-	Line   int
-	Column int
+	Start  Pos
+	End    Pos
+	Source *Source
 }
 
-func New(t Type, v string, l int, c int) Token {
+func New(t Type, v string, start Pos, end Pos) Token {
 	return Token{
-		Type:   t,
-		Value:  v,
-		Line:   l,
-		Column: c,
+		Type:  t,
+		Value: v,
+		Start: start,
+		End:   end,
 	}
 }
 
+// Span returns the range of source covered by the token.
+func (t Token) Span() Span {
+	return Span{Start: t.Start, End: t.End}
+}
+
 func (t Token) String() string {
-	return fmt.Sprintf("[ type: %s, value: %v, position: %d:%d ]", GetStringFromTokenType(t.Type), t.Value, t.Line, t.Column)
+	return fmt.Sprintf("[ type: %s, value: %v, position: %d:%d ]", GetStringFromTokenType(t.Type), t.Value, t.Start.Line, t.Start.Column)
 }
 
 const (
@@ -95,6 +107,21 @@ const (
 	Finally
 	True
 	False
+	Arrow
+	Question
+	OptionalChain
+	NullishCoalescing
+	Ellipsis
+	Power
+	BitAnd
+	BitOr
+	BitXor
+	BitNot
+	ShiftLeft
+	ShiftRight
+	// Operator is an operator spelling defined by an embedder, whose value is
+	// the spelling.
+	Operator
 )
 
 var keywordsToTypes = map[string]Type{
@@ -135,14 +162,10 @@ var keywordsToTypes = map[string]Type{
 	"false":      False,
 }
 
+// GetKeywordType returns the type of the keyword in the full language, or
+// Ident if kw is not a keyword.
 func GetKeywordType(kw string) Type {
-	t, exists := keywordsToTypes[kw]
-
-	if exists {
-		return t
-	}
-
-	return Ident
+	return Full.KeywordType(kw)
 }
 
 var tokenTypeToString = map[Type]string{
@@ -214,6 +237,19 @@ var tokenTypeToString = map[Type]string{
 	Finally:            "Finally",
 	True:               "True",
 	False:              "False",
+	Arrow:              "Arrow",
+	Question:           "Question",
+	OptionalChain:      "OptionalChain",
+	NullishCoalescing:  "NullishCoalescing",
+	Ellipsis:           "Ellipsis",
+	Power:              "Power",
+	BitAnd:             "BitAnd",
+	BitOr:              "BitOr",
+	BitXor:             "BitXor",
+	BitNot:             "BitNot",
+	ShiftLeft:          "ShiftLeft",
+	ShiftRight:         "ShiftRight",
+	Operator:           "Operator",
 }
 
 func GetStringFromTokenType(t Type) string {
@@ -10,626 +10,626 @@ func TestToken_New(t *testing.T) {
 		name     string
 		t        Type
 		lit      string
-		ln       int
-		col      int
+		start    Pos
+		end      Pos
 		expected Token
 	}{
 		{
-			name: "Illegal",
-			t:    Illegal,
-			lit:  "Illegal",
-			ln:   1,
-			col:  2,
+			name:  "Illegal",
+			t:     Illegal,
+			lit:   "Illegal",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Illegal,
-				Value:  "Illegal",
-				Line:   1,
-				Column: 2,
+				Type:  Illegal,
+				Value: "Illegal",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Eof",
-			t:    Eof,
-			lit:  "Eof",
-			ln:   1,
-			col:  2,
+			name:  "Eof",
+			t:     Eof,
+			lit:   "Eof",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Eof,
-				Value:  "Eof",
-				Line:   1,
-				Column: 2,
+				Type:  Eof,
+				Value: "Eof",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Ident",
-			t:    Ident,
-			lit:  "Ident",
-			ln:   1,
-			col:  2,
+			name:  "Ident",
+			t:     Ident,
+			lit:   "Ident",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Ident,
-				Value:  "Ident",
-				Line:   1,
-				Column: 2,
+				Type:  Ident,
+				Value: "Ident",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Assign",
-			t:    Assign,
-			lit:  "Assign",
-			ln:   1,
-			col:  2,
+			name:  "Assign",
+			t:     Assign,
+			lit:   "Assign",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Assign,
-				Value:  "Assign",
-				Line:   1,
-				Column: 2,
+				Type:  Assign,
+				Value: "Assign",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Plus",
-			t:    Plus,
-			lit:  "Plus",
-			ln:   1,
-			col:  2,
+			name:  "Plus",
+			t:     Plus,
+			lit:   "Plus",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Plus,
-				Value:  "Plus",
-				Line:   1,
-				Column: 2,
+				Type:  Plus,
+				Value: "Plus",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Minus",
-			t:    Minus,
-			lit:  "Minus",
-			ln:   1,
-			col:  2,
+			name:  "Minus",
+			t:     Minus,
+			lit:   "Minus",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Minus,
-				Value:  "Minus",
-				Line:   1,
-				Column: 2,
+				Type:  Minus,
+				Value: "Minus",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Divide",
-			t:    Divide,
-			lit:  "Divide",
-			ln:   1,
-			col:  2,
+			name:  "Divide",
+			t:     Divide,
+			lit:   "Divide",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Divide,
-				Value:  "Divide",
-				Line:   1,
-				Column: 2,
+				Type:  Divide,
+				Value: "Divide",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Multiply",
-			t:    Multiply,
-			lit:  "Multiply",
-			ln:   1,
-			col:  2,
+			name:  "Multiply",
+			t:     Multiply,
+			lit:   "Multiply",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Multiply,
-				Value:  "Multiply",
-				Line:   1,
-				Column: 2,
+				Type:  Multiply,
+				Value: "Multiply",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Modulus",
-			t:    Modulus,
-			lit:  "Modulus",
-			ln:   1,
-			col:  2,
+			name:  "Modulus",
+			t:     Modulus,
+			lit:   "Modulus",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Modulus,
-				Value:  "Modulus",
-				Line:   1,
-				Column: 2,
+				Type:  Modulus,
+				Value: "Modulus",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Comma",
-			t:    Comma,
-			lit:  "Comma",
-			ln:   1,
-			col:  2,
+			name:  "Comma",
+			t:     Comma,
+			lit:   "Comma",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Comma,
-				Value:  "Comma",
-				Line:   1,
-				Column: 2,
+				Type:  Comma,
+				Value: "Comma",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "FullStop",
-			t:    FullStop,
-			lit:  "FullStop",
-			ln:   1,
-			col:  2,
+			name:  "FullStop",
+			t:     FullStop,
+			lit:   "FullStop",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   FullStop,
-				Value:  "FullStop",
-				Line:   1,
-				Column: 2,
+				Type:  FullStop,
+				Value: "FullStop",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Semicolon",
-			t:    Semicolon,
-			lit:  "Semicolon",
-			ln:   1,
-			col:  2,
+			name:  "Semicolon",
+			t:     Semicolon,
+			lit:   "Semicolon",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Semicolon,
-				Value:  "Semicolon",
-				Line:   1,
-				Column: 2,
+				Type:  Semicolon,
+				Value: "Semicolon",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Colon",
-			t:    Colon,
-			lit:  "Colon",
-			ln:   1,
-			col:  2,
+			name:  "Colon",
+			t:     Colon,
+			lit:   "Colon",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Colon,
-				Value:  "Colon",
-				Line:   1,
-				Column: 2,
+				Type:  Colon,
+				Value: "Colon",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "LParen",
-			t:    LParen,
-			lit:  "LParen",
-			ln:   1,
-			col:  2,
+			name:  "LParen",
+			t:     LParen,
+			lit:   "LParen",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   LParen,
-				Value:  "LParen",
-				Line:   1,
-				Column: 2,
+				Type:  LParen,
+				Value: "LParen",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "RParen",
-			t:    RParen,
-			lit:  "RParen",
-			ln:   1,
-			col:  2,
+			name:  "RParen",
+			t:     RParen,
+			lit:   "RParen",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   RParen,
-				Value:  "RParen",
-				Line:   1,
-				Column: 2,
+				Type:  RParen,
+				Value: "RParen",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "LBrace",
-			t:    LBrace,
-			lit:  "LBrace",
-			ln:   1,
-			col:  2,
+			name:  "LBrace",
+			t:     LBrace,
+			lit:   "LBrace",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   LBrace,
-				Value:  "LBrace",
-				Line:   1,
-				Column: 2,
+				Type:  LBrace,
+				Value: "LBrace",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "RBrace",
-			t:    RBrace,
-			lit:  "RBrace",
-			ln:   1,
-			col:  2,
+			name:  "RBrace",
+			t:     RBrace,
+			lit:   "RBrace",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   RBrace,
-				Value:  "RBrace",
-				Line:   1,
-				Column: 2,
+				Type:  RBrace,
+				Value: "RBrace",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "LBracket",
-			t:    LBracket,
-			lit:  "LBracket",
-			ln:   1,
-			col:  2,
+			name:  "LBracket",
+			t:     LBracket,
+			lit:   "LBracket",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   LBracket,
-				Value:  "LBracket",
-				Line:   1,
-				Column: 2,
+				Type:  LBracket,
+				Value: "LBracket",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "RBracket",
-			t:    RBracket,
-			lit:  "RBracket",
-			ln:   1,
-			col:  2,
+			name:  "RBracket",
+			t:     RBracket,
+			lit:   "RBracket",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   RBracket,
-				Value:  "RBracket",
-				Line:   1,
-				Column: 2,
+				Type:  RBracket,
+				Value: "RBracket",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Percent",
-			t:    Percent,
-			lit:  "Percent",
-			ln:   1,
-			col:  2,
+			name:  "Percent",
+			t:     Percent,
+			lit:   "Percent",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Percent,
-				Value:  "Percent",
-				Line:   1,
-				Column: 2,
+				Type:  Percent,
+				Value: "Percent",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "DoubleQuote",
-			t:    DoubleQuote,
-			lit:  "DoubleQuote",
-			ln:   1,
-			col:  2,
+			name:  "DoubleQuote",
+			t:     DoubleQuote,
+			lit:   "DoubleQuote",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   DoubleQuote,
-				Value:  "DoubleQuote",
-				Line:   1,
-				Column: 2,
+				Type:  DoubleQuote,
+				Value: "DoubleQuote",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "SingleQuote",
-			t:    SingleQuote,
-			lit:  "SingleQuote",
-			ln:   1,
-			col:  2,
+			name:  "SingleQuote",
+			t:     SingleQuote,
+			lit:   "SingleQuote",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   SingleQuote,
-				Value:  "SingleQuote",
-				Line:   1,
-				Column: 2,
+				Type:  SingleQuote,
+				Value: "SingleQuote",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "GreaterThan",
-			t:    GreaterThan,
-			lit:  "GreaterThan",
-			ln:   1,
-			col:  2,
+			name:  "GreaterThan",
+			t:     GreaterThan,
+			lit:   "GreaterThan",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   GreaterThan,
-				Value:  "GreaterThan",
-				Line:   1,
-				Column: 2,
+				Type:  GreaterThan,
+				Value: "GreaterThan",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "LessThan",
-			t:    LessThan,
-			lit:  "LessThan",
-			ln:   1,
-			col:  2,
+			name:  "LessThan",
+			t:     LessThan,
+			lit:   "LessThan",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   LessThan,
-				Value:  "LessThan",
-				Line:   1,
-				Column: 2,
+				Type:  LessThan,
+				Value: "LessThan",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "GreaterThanOrEqual",
-			t:    GreaterThanOrEqual,
-			lit:  "GreaterThanOrEqual",
-			ln:   1,
-			col:  2,
+			name:  "GreaterThanOrEqual",
+			t:     GreaterThanOrEqual,
+			lit:   "GreaterThanOrEqual",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   GreaterThanOrEqual,
-				Value:  "GreaterThanOrEqual",
-				Line:   1,
-				Column: 2,
+				Type:  GreaterThanOrEqual,
+				Value: "GreaterThanOrEqual",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "LessThanOrEqual",
-			t:    LessThanOrEqual,
-			lit:  "LessThanOrEqual",
-			ln:   1,
-			col:  2,
+			name:  "LessThanOrEqual",
+			t:     LessThanOrEqual,
+			lit:   "LessThanOrEqual",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   LessThanOrEqual,
-				Value:  "LessThanOrEqual",
-				Line:   1,
-				Column: 2,
+				Type:  LessThanOrEqual,
+				Value: "LessThanOrEqual",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Equal",
-			t:    Equal,
-			lit:  "Equal",
-			ln:   1,
-			col:  2,
+			name:  "Equal",
+			t:     Equal,
+			lit:   "Equal",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Equal,
-				Value:  "Equal",
-				Line:   1,
-				Column: 2,
+				Type:  Equal,
+				Value: "Equal",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "NotEqual",
-			t:    NotEqual,
-			lit:  "NotEqual",
-			ln:   1,
-			col:  2,
+			name:  "NotEqual",
+			t:     NotEqual,
+			lit:   "NotEqual",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   NotEqual,
-				Value:  "NotEqual",
-				Line:   1,
-				Column: 2,
+				Type:  NotEqual,
+				Value: "NotEqual",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "And",
-			t:    And,
-			lit:  "And",
-			ln:   1,
-			col:  2,
+			name:  "And",
+			t:     And,
+			lit:   "And",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   And,
-				Value:  "And",
-				Line:   1,
-				Column: 2,
+				Type:  And,
+				Value: "And",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Or",
-			t:    Or,
-			lit:  "Or",
-			ln:   1,
-			col:  2,
+			name:  "Or",
+			t:     Or,
+			lit:   "Or",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Or,
-				Value:  "Or",
-				Line:   1,
-				Column: 2,
+				Type:  Or,
+				Value: "Or",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Not",
-			t:    Not,
-			lit:  "Not",
-			ln:   1,
-			col:  2,
+			name:  "Not",
+			t:     Not,
+			lit:   "Not",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Not,
-				Value:  "Not",
-				Line:   1,
-				Column: 2,
+				Type:  Not,
+				Value: "Not",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Number",
-			t:    Number,
-			lit:  "Number",
-			ln:   1,
-			col:  2,
+			name:  "Number",
+			t:     Number,
+			lit:   "Number",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Number,
-				Value:  "Number",
-				Line:   1,
-				Column: 2,
+				Type:  Number,
+				Value: "Number",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "String",
-			t:    String,
-			lit:  "String",
-			ln:   1,
-			col:  2,
+			name:  "String",
+			t:     String,
+			lit:   "String",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   String,
-				Value:  "String",
-				Line:   1,
-				Column: 2,
+				Type:  String,
+				Value: "String",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "If",
-			t:    If,
-			lit:  "If",
-			ln:   1,
-			col:  2,
+			name:  "If",
+			t:     If,
+			lit:   "If",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   If,
-				Value:  "If",
-				Line:   1,
-				Column: 2,
+				Type:  If,
+				Value: "If",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Else",
-			t:    Else,
-			lit:  "Else",
-			ln:   1,
-			col:  2,
+			name:  "Else",
+			t:     Else,
+			lit:   "Else",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Else,
-				Value:  "Else",
-				Line:   1,
-				Column: 2,
+				Type:  Else,
+				Value: "Else",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "While",
-			t:    While,
-			lit:  "While",
-			ln:   1,
-			col:  2,
+			name:  "While",
+			t:     While,
+			lit:   "While",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   While,
-				Value:  "While",
-				Line:   1,
-				Column: 2,
+				Type:  While,
+				Value: "While",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Do",
-			t:    Do,
-			lit:  "Do",
-			ln:   1,
-			col:  2,
+			name:  "Do",
+			t:     Do,
+			lit:   "Do",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Do,
-				Value:  "Do",
-				Line:   1,
-				Column: 2,
+				Type:  Do,
+				Value: "Do",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "For",
-			t:    For,
-			lit:  "For",
-			ln:   1,
-			col:  2,
+			name:  "For",
+			t:     For,
+			lit:   "For",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   For,
-				Value:  "For",
-				Line:   1,
-				Column: 2,
+				Type:  For,
+				Value: "For",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Function",
-			t:    Function,
-			lit:  "Function",
-			ln:   1,
-			col:  2,
+			name:  "Function",
+			t:     Function,
+			lit:   "Function",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Function,
-				Value:  "Function",
-				Line:   1,
-				Column: 2,
+				Type:  Function,
+				Value: "Function",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Define",
-			t:    Define,
-			lit:  "Define",
-			ln:   1,
-			col:  2,
+			name:  "Define",
+			t:     Define,
+			lit:   "Define",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Define,
-				Value:  "Define",
-				Line:   1,
-				Column: 2,
+				Type:  Define,
+				Value: "Define",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Const",
-			t:    Const,
-			lit:  "Const",
-			ln:   1,
-			col:  2,
+			name:  "Const",
+			t:     Const,
+			lit:   "Const",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Const,
-				Value:  "Const",
-				Line:   1,
-				Column: 2,
+				Type:  Const,
+				Value: "Const",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Let",
-			t:    Let,
-			lit:  "Let",
-			ln:   1,
-			col:  2,
+			name:  "Let",
+			t:     Let,
+			lit:   "Let",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Let,
-				Value:  "Let",
-				Line:   1,
-				Column: 2,
+				Type:  Let,
+				Value: "Let",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Class",
-			t:    Class,
-			lit:  "Class",
-			ln:   1,
-			col:  2,
+			name:  "Class",
+			t:     Class,
+			lit:   "Class",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Class,
-				Value:  "Class",
-				Line:   1,
-				Column: 2,
+				Type:  Class,
+				Value: "Class",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Include",
-			t:    Include,
-			lit:  "Include",
-			ln:   1,
-			col:  2,
+			name:  "Include",
+			t:     Include,
+			lit:   "Include",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Include,
-				Value:  "Include",
-				Line:   1,
-				Column: 2,
+				Type:  Include,
+				Value: "Include",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "Interface",
-			t:    Interface,
-			lit:  "Interface",
-			ln:   1,
-			col:  2,
+			name:  "Interface",
+			t:     Interface,
+			lit:   "Interface",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   Interface,
-				Value:  "Interface",
-				Line:   1,
-				Column: 2,
+				Type:  Interface,
+				Value: "Interface",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "True",
-			t:    True,
-			lit:  "True",
-			ln:   1,
-			col:  2,
+			name:  "True",
+			t:     True,
+			lit:   "True",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   True,
-				Value:  "True",
-				Line:   1,
-				Column: 2,
+				Type:  True,
+				Value: "True",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 		{
-			name: "False",
-			t:    False,
-			lit:  "False",
-			ln:   1,
-			col:  2,
+			name:  "False",
+			t:     False,
+			lit:   "False",
+			start: Pos{Offset: 2, Line: 1, Column: 2},
+			end:   Pos{Offset: 3, Line: 1, Column: 3},
 			expected: Token{
-				Type:   False,
-				Value:  "False",
-				Line:   1,
-				Column: 2,
+				Type:  False,
+				Value: "False",
+				Start: Pos{Offset: 2, Line: 1, Column: 2},
+				End:   Pos{Offset: 3, Line: 1, Column: 3},
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			actual := New(tt.t, tt.lit, tt.ln, tt.col)
+			actual := New(tt.t, tt.lit, tt.start, tt.end)
 			if !reflect.DeepEqual(actual, tt.expected) {
 				t.Errorf("Expected: %v, Actual: %v", tt.expected, actual)
 			}
@@ -642,31 +642,31 @@ func TestToken_String(t *testing.T) {
 		name     string
 		t        Type
 		lit      string
-		ln       int
-		col      int
+		start    Pos
+		end      Pos
 		expected string
 	}{
 		{
 			name:     "Illegal",
 			t:        Illegal,
 			lit:      "Illegal",
-			ln:       1,
-			col:      2,
+			start:    Pos{Offset: 2, Line: 1, Column: 2},
+			end:      Pos{Offset: 3, Line: 1, Column: 3},
 			expected: "[ type: Illegal, value: Illegal, position: 1:2 ]",
 		},
 		{
 			name:     "Eof",
 			t:        Eof,
 			lit:      "Eof",
-			ln:       1,
-			col:      2,
+			start:    Pos{Offset: 2, Line: 1, Column: 2},
+			end:      Pos{Offset: 3, Line: 1, Column: 3},
 			expected: "[ type: Eof, value: Eof, position: 1:2 ]",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			actual := New(tt.t, tt.lit, tt.ln, tt.col).String()
+			actual := New(tt.t, tt.lit, tt.start, tt.end).String()
 			if !reflect.DeepEqual(actual, tt.expected) {
 				t.Errorf("Expected: %v, Actual: %v", tt.expected, actual)
 			}
@@ -0,0 +1,67 @@
+package token
+
+import "strings"
+
+// TriviaKind classifies source text that carries no meaning for the parser.
+type TriviaKind int
+
+const (
+	Whitespace TriviaKind = iota
+	Newline
+	Comment
+)
+
+var triviaKindToString = map[TriviaKind]string{
+	Whitespace: "Whitespace",
+	Newline:    "Newline",
+	Comment:    "Comment",
+}
+
+func (k TriviaKind) String() string {
+	name, exists := triviaKindToString[k]
+
+	if exists {
+		return name
+	}
+
+	return "UNKNOWN"
+}
+
+// Trivia is a run of whitespace, a newline or a comment attached to a token.
+type Trivia struct {
+	Kind  TriviaKind
+	Text  string
+	Start Pos
+	End   Pos
+}
+
+// Source is the exact source of a token, as recorded in trivia mode. Raw is
+// the token's own text, which differs from its Value for strings and
+// normalised identifiers. Trailing trivia runs up to and including the next
+// newline; everything else before a token is its leading trivia.
+type Source struct {
+	Raw      string
+	Leading  []Trivia
+	Trailing []Trivia
+}
+
+// FullText returns the token's source text together with its leading and
+// trailing trivia. Concatenating the FullText of every token produced by a
+// lexer in trivia mode reproduces its input; outside trivia mode it is empty.
+func (t Token) FullText() string {
+	if t.Source == nil {
+		return ""
+	}
+
+	var builder strings.Builder
+
+	for _, tr := range t.Source.Leading {
+		builder.WriteString(tr.Text)
+	}
+	builder.WriteString(t.Source.Raw)
+	for _, tr := range t.Source.Trailing {
+		builder.WriteString(tr.Text)
+	}
+
+	return builder.String()
+}
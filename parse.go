@@ -15,5 +15,15 @@ func main() {
 	fmt.Printf("Hello %s! This is the programming language!\n",
 		u.Username)
 	fmt.Printf("Feel free to type in commands\n")
-	repl.Start(os.Stdin, os.Stdout)
+	repl.Start(os.Stdin, os.Stdout, repl.WithColor(isTerminal(os.Stdout)))
+}
+
+// isTerminal reports whether f is a terminal rather than a file or pipe, so
+// that colour is only used where it will be displayed.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
 }
@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"fmt"
+	"lang/diag"
+	"lang/lexer/token"
+)
+
+// Diagnostic codes reported by the parser.
+const (
+	// CodeUnexpectedToken is reported when the next token is not the one the
+	// grammar requires, such as a missing = in a let declaration.
+	CodeUnexpectedToken = "P0001"
+	// CodeExpectedExpression is reported for a token that cannot start an
+	// expression.
+	CodeExpectedExpression = "P0002"
+	// CodeInvalidNumber is reported for a number literal that cannot be
+	// represented.
+	CodeInvalidNumber = "P0003"
+	// CodeMissingTerminator is reported when a statement is followed by more
+	// input on the same line.
+	CodeMissingTerminator = "P0004"
+	// CodeAmbiguousLineBreak is reported when a line break ends a statement but
+	// the next line could also have continued it.
+	CodeAmbiguousLineBreak = "P0005"
+	// CodeInvalidPattern is reported for a binding that is neither a name nor a
+	// destructuring pattern.
+	CodeInvalidPattern = "P0006"
+	// CodeMisplacedRest is reported for a rest parameter or element that is not
+	// last.
+	CodeMisplacedRest = "P0007"
+	// CodeInvalidDefault is reported for a default value where none is allowed.
+	CodeInvalidDefault = "P0008"
+	// CodeUnclosedBlock is reported when the input ends inside a block.
+	CodeUnclosedBlock = "P0009"
+	// CodeTooManyErrors is reported when parsing stops after reaching the limit
+	// set by WithMaxErrors.
+	CodeTooManyErrors = "P0010"
+	// CodeNonAssociative is reported when a non-associative operator is
+	// chained with another of the same precedence.
+	CodeNonAssociative = "P0011"
+	// CodeInvalidAssignmentTarget is reported when the left side of = is not a
+	// name or a member expression.
+	CodeInvalidAssignmentTarget = "P0012"
+	// CodeTrailingInput is reported when input remains after the expression or
+	// statement parsed by ParseExpression or ParseStatement.
+	CodeTrailingInput = "P0013"
+)
+
+// Errors returns the messages of the errors found while parsing. Use
+// Diagnostics for their positions and codes.
+func (p *Parser) Errors() []string {
+	errors := make([]string, len(p.diagnostics))
+	for i, d := range p.diagnostics {
+		errors[i] = d.Message
+	}
+	return errors
+}
+
+// Diagnostics returns the problems found by the lexer and the parser so far,
+// ordered by position.
+func (p *Parser) Diagnostics() diag.List {
+	diagnostics := append(p.l.Diagnostics(), p.diagnostics...)
+	diagnostics.Sort()
+	return diagnostics
+}
+
+// errorf records an error at the given span and returns it so that labels,
+// notes and fixes can be added. Errors reported while the parser is already
+// recovering from an earlier one in the same statement are usually caused by
+// it, so they are returned but not recorded.
+func (p *Parser) errorf(code string, span token.Span, format string, args ...any) *diag.Diagnostic {
+	d := diag.Errorf(code, span, format, args...)
+	if p.panicking || p.stopped {
+		return d
+	}
+
+	p.panicking = true
+	p.diagnostics = append(p.diagnostics, d)
+	p.errors++
+
+	if p.maxErrors > 0 && p.errors >= p.maxErrors {
+		p.stopped = true
+		p.diagnostics = append(p.diagnostics,
+			diag.Errorf(CodeTooManyErrors, span, "too many errors, stopping after %d", p.maxErrors))
+	}
+
+	return d
+}
+
+// reportedByLexer reports whether t is an Illegal token, whose error the
+// lexer has already recorded. The parser then recovers from it as from any
+// other error, without adding a second diagnostic.
+func (p *Parser) reportedByLexer(t token.Token) bool {
+	if t.Type != token.Illegal {
+		return false
+	}
+	p.panicking = true
+	return true
+}
+
+// suggestKeyword adds a fix to d replacing tok with the keyword of the
+// lexer's dialect that it most likely misspells, if there is one.
+func (p *Parser) suggestKeyword(d *diag.Diagnostic, tok token.Token) {
+	if tok.Type != token.Ident {
+		return
+	}
+
+	if kw, ok := diag.ClosestMatch(tok.Value, p.l.Dialect().Keywords()); ok {
+		d.WithFix(fmt.Sprintf("did you mean %q", kw), tok.Span(), kw)
+	}
+}
+
+// insertionAfter returns the empty span just past t, where a fix inserts text.
+func insertionAfter(t token.Token) token.Span {
+	return token.Span{Start: t.End, End: t.End}
+}
@@ -0,0 +1,197 @@
+package parser
+
+import (
+	"fmt"
+	"lang/ast/expressions"
+	"lang/lexer/token"
+)
+
+// Associativity determines how a chain of operators of the same precedence
+// is grouped.
+type Associativity int
+
+const (
+	// LeftAssociative operators group from the left, so a - b - c is
+	// (a - b) - c.
+	LeftAssociative Associativity = iota
+	// RightAssociative operators group from the right, so a ** b ** c is
+	// a ** (b ** c).
+	RightAssociative
+	// NonAssociative operators cannot be chained without parentheses.
+	NonAssociative
+)
+
+// operatorInfo describes an operator registered with WithInfixOperator or
+// WithPostfixOperator.
+type operatorInfo struct {
+	precedence    int
+	associativity Associativity
+}
+
+// WithPrefixOperator registers spelling as a prefix operator, parsed as a
+// PrefixExpression whose operand is parsed at the given precedence. With
+// PREFIX the operator binds tighter than any infix operator, while a lower
+// precedence such as LOGICAL_AND lets not x in xs negate a whole comparison.
+// Spellings that are not identifiers are also defined in the lexer, and a
+// word operator can no longer be used as a name at the start of an
+// expression.
+func WithPrefixOperator(spelling string, precedence int) Option {
+	return func(p *Parser) {
+		p.l.DefineOperator(spelling)
+		if p.prefixOperators == nil {
+			p.prefixOperators = make(map[string]int)
+		}
+		p.prefixOperators[spelling] = precedence
+	}
+}
+
+// WithInfixOperator registers spelling as an infix operator, parsed as an
+// InfixExpression with the given precedence, such as EQUALS, and
+// associativity. Word operators such as in or matches are recognised after an
+// operand, so they can still be used as names elsewhere. It panics if
+// spelling is already a postfix operator.
+func WithInfixOperator(spelling string, precedence int, associativity Associativity) Option {
+	return func(p *Parser) {
+		if _, exists := p.postfixOperators[spelling]; exists {
+			panic(fmt.Sprintf("parser: %q is already a postfix operator", spelling))
+		}
+		p.l.DefineOperator(spelling)
+		if p.infixOperators == nil {
+			p.infixOperators = make(map[string]operatorInfo)
+		}
+		p.infixOperators[spelling] = operatorInfo{precedence: precedence, associativity: associativity}
+	}
+}
+
+// WithPostfixOperator registers spelling as a postfix operator, parsed as a
+// PostfixExpression with the given precedence. It panics if spelling is
+// already an infix operator.
+func WithPostfixOperator(spelling string, precedence int) Option {
+	return func(p *Parser) {
+		if _, exists := p.infixOperators[spelling]; exists {
+			panic(fmt.Sprintf("parser: %q is already an infix operator", spelling))
+		}
+		p.l.DefineOperator(spelling)
+		if p.postfixOperators == nil {
+			p.postfixOperators = make(map[string]operatorInfo)
+		}
+		p.postfixOperators[spelling] = operatorInfo{precedence: precedence}
+	}
+}
+
+// operatorSpelling returns the spelling under which t may have been
+// registered as an operator: the value of an operator token, including
+// built-in punctuation such as !, or of a word, whether lexed as an
+// identifier or a keyword. Registering a built-in spelling therefore extends
+// or overrides the built-in token.
+func (p *Parser) operatorSpelling(t token.Token) (string, bool) {
+	if p.l.IsOperator(t) || p.l.Dialect().KeywordType(t.Value) == t.Type {
+		return t.Value, true
+	}
+	return "", false
+}
+
+// prefixParseFn returns the function parsing an expression that starts with
+// t, or nil if none can.
+func (p *Parser) prefixParseFn(t token.Token) prefixParseFn {
+	if spelling, ok := p.operatorSpelling(t); ok {
+		if _, exists := p.prefixOperators[spelling]; exists {
+			return p.parsePrefixExpression
+		}
+	}
+	if fn := p.prefixParseFns[t.Type]; fn != nil {
+		return fn
+	}
+	if p.l.Dialect().IsContextual(t.Type) {
+		return p.parseIdentifier
+	}
+	return nil
+}
+
+// infixParseFn returns the function parsing an expression continued by t,
+// or nil if t cannot continue one.
+func (p *Parser) infixParseFn(t token.Token) infixParseFn {
+	if spelling, ok := p.operatorSpelling(t); ok {
+		if _, exists := p.infixOperators[spelling]; exists {
+			return p.parseInfixExpression
+		}
+		if _, exists := p.postfixOperators[spelling]; exists {
+			return p.parsePostfixExpression
+		}
+	}
+	return p.infixParseFns[t.Type]
+}
+
+// precedence returns how tightly t binds as an infix or postfix operator.
+func (p *Parser) precedence(t token.Token) int {
+	if spelling, ok := p.operatorSpelling(t); ok {
+		if op, exists := p.infixOperators[spelling]; exists {
+			return op.precedence
+		}
+		if op, exists := p.postfixOperators[spelling]; exists {
+			return op.precedence
+		}
+	}
+	if prec, ok := precedences[t.Type]; ok {
+		return prec
+	}
+	return LOWEST
+}
+
+// prefixPrecedence returns the precedence at which the operand of the prefix
+// operator t is parsed. The built-in operators take ** into their operand, so
+// -2 ** 2 is -(2 ** 2) as in mathematics.
+func (p *Parser) prefixPrecedence(t token.Token) int {
+	if spelling, ok := p.operatorSpelling(t); ok {
+		if prec, exists := p.prefixOperators[spelling]; exists {
+			return prec
+		}
+	}
+	return PRODUCT
+}
+
+// associativity returns how chains of the infix operator t are grouped.
+func (p *Parser) associativity(t token.Token) Associativity {
+	if spelling, ok := p.operatorSpelling(t); ok {
+		if op, exists := p.infixOperators[spelling]; exists {
+			return op.associativity
+		}
+	}
+	return associativities[t.Type]
+}
+
+// associativities lists the built-in operators that are not left-associative.
+// Assignment and exponentiation group from the right, so a = b = c assigns c
+// to both and 2 ** 3 ** 2 is 2 ** 9. Comparisons cannot be chained, since
+// a < b < c would otherwise compare the boolean a < b with c.
+var associativities = map[token.Type]Associativity{
+	token.Assign:             RightAssociative,
+	token.Power:              RightAssociative,
+	token.Equal:              NonAssociative,
+	token.NotEqual:           NonAssociative,
+	token.LessThan:           NonAssociative,
+	token.GreaterThan:        NonAssociative,
+	token.LessThanOrEqual:    NonAssociative,
+	token.GreaterThanOrEqual: NonAssociative,
+}
+
+// operandPrecedence returns the precedence at which to parse the right
+// operand of an operator. Parsing it just below the operator's own
+// precedence lets a following operator of the same precedence bind to the
+// operand first, grouping the chain from the right.
+func operandPrecedence(precedence int, associativity Associativity) int {
+	if associativity == RightAssociative {
+		return precedence - 1
+	}
+	return precedence
+}
+
+func (p *Parser) parsePostfixExpression(left expressions.Expression) expressions.Expression {
+	defer p.untrace(p.trace("parsePostfixExpression"))
+
+	return &expressions.PostfixExpression{
+		Token:    p.currentToken,
+		Left:     left,
+		Operator: p.currentToken.Value,
+	}
+}
@@ -0,0 +1,27 @@
+package parser
+
+import "lang/lexer"
+
+// Option configures a Parser.
+type Option func(*Parser)
+
+// WithMaxErrors stops parsing once n errors have been reported, adding a
+// final error to say so. A limit of zero or less, the default, reports every
+// error.
+func WithMaxErrors(n int) Option {
+	return func(p *Parser) {
+		p.maxErrors = n
+	}
+}
+
+// WithLexerOptions configures the lexer the parser reads from, such as with
+// lexer.WithTabWidth or lexer.WithDialect. It is most useful with ParseString,
+// ParseFile and ParseFS, which create the lexer themselves. The options are
+// applied before the parser reads its first token.
+func WithLexerOptions(opts ...lexer.Option) Option {
+	return func(p *Parser) {
+		for _, opt := range opts {
+			opt(p.l)
+		}
+	}
+}
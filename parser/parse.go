@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"io/fs"
+	"lang/ast/expressions"
+	"lang/ast/statements"
+	"lang/lexer"
+	"lang/lexer/token"
+	"os"
+)
+
+// ParseString parses src as a program. The program is returned even when
+// there are errors, with an ErrorNode in place of each statement that could
+// not be parsed, and the error is a diag.List holding every problem found.
+func ParseString(src string, opts ...Option) (*statements.Program, error) {
+	return parseProgram(lexer.NewFromBytes([]byte(src)), opts)
+}
+
+// ParseFile reads and parses the program in the file at path, which is
+// recorded in every position so that diagnostics read like
+// config.lang:12:4. Results are as for ParseString, except that a failure to
+// read the file is returned as is, with a nil program.
+func ParseFile(path string, opts ...Option) (*statements.Program, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseProgram(lexer.NewFromBytes(src, lexer.WithFilename(path)), opts)
+}
+
+// ParseFS is ParseFile for the file name in fsys.
+func ParseFS(fsys fs.FS, name string, opts ...Option) (*statements.Program, error) {
+	src, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return parseProgram(lexer.NewFromBytes(src, lexer.WithFilename(name)), opts)
+}
+
+func parseProgram(l *lexer.Lexer, opts []Option) (*statements.Program, error) {
+	p := New(l, opts...)
+	program := p.ParseProgram()
+	return program, p.Diagnostics().Err()
+}
+
+// ParseExpression parses src as a single expression, such as a filter in a
+// configuration file. The whole of src must be consumed. On failure the error
+// is a diag.List holding every problem found.
+func ParseExpression(src string, opts ...Option) (expressions.Expression, error) {
+	p := New(lexer.NewFromBytes([]byte(src)), opts...)
+
+	expression := p.parseExpression(LOWEST)
+	if expression != nil {
+		p.expectEnd()
+	}
+
+	if err := p.Diagnostics().Err(); err != nil {
+		return nil, err
+	}
+	return expression, nil
+}
+
+// ParseStatement parses src as a single statement. The whole of src must be
+// consumed, although the statement may end with a semicolon. On failure the
+// error is a diag.List holding every problem found.
+func ParseStatement(src string, opts ...Option) (statements.Statement, error) {
+	p := New(lexer.NewFromBytes([]byte(src)), opts...)
+
+	statement := p.parseStatement()
+	if statement != nil {
+		p.expectEnd()
+	}
+
+	if err := p.Diagnostics().Err(); err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// expectEnd reports an error if any input follows the current token.
+func (p *Parser) expectEnd() {
+	if p.peekTokenIs(token.Eof) || p.reportedByLexer(p.peekToken) {
+		return
+	}
+
+	p.errorf(CodeTrailingInput, p.peekToken.Span(), "expected end of input, got %s instead",
+		token.GetStringFromTokenType(p.peekToken.Type))
+}
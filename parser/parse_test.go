@@ -0,0 +1,220 @@
+package parser
+
+import (
+	"errors"
+	"io/fs"
+	"lang/ast/patterns"
+	"lang/ast/statements"
+	"lang/diag"
+	"lang/lexer"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a + b * c", "(a + (b * c))"},
+		{"  x.y ?? 1  ", "((x.y) ?? 1)"},
+		{"a &&\nb", "(a && b)"},
+		{"(a, b) => a + b", "((a, b) => (a + b))"},
+	}
+
+	for _, tt := range tests {
+		expression, err := ParseExpression(tt.input)
+		if err != nil {
+			t.Errorf("ParseExpression(%q) returned %v", tt.input, err)
+			continue
+		}
+		if actual := expression.String(); actual != tt.expected {
+			t.Errorf("ParseExpression(%q) = %q, want %q", tt.input, actual, tt.expected)
+		}
+	}
+}
+
+func TestParseExpression_Errors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a + b;", "1:6: error[P0013]: expected end of input, got Semicolon instead"},
+		{"a b", "1:3: error[P0013]: expected end of input, got Ident instead"},
+		{"a\n+ b", "2:1: error[P0013]: expected end of input, got Plus instead"},
+		{"a +", "1:4: error[P0002]: no prefix parse function for Eof found"},
+		{"", "1:1: error[P0002]: no prefix parse function for Eof found"},
+		{"a # b", "1:3: error[L0002]: invalid character '#'"},
+	}
+
+	for _, tt := range tests {
+		expression, err := ParseExpression(tt.input)
+		if err == nil {
+			t.Errorf("ParseExpression(%q) = %v, want an error", tt.input, expression)
+			continue
+		}
+		if expression != nil {
+			t.Errorf("ParseExpression(%q) returned %v alongside an error", tt.input, expression)
+		}
+
+		var list diag.List
+		if !errors.As(err, &list) {
+			t.Errorf("ParseExpression(%q) returned %T, want diag.List", tt.input, err)
+		}
+		if err.Error() != tt.expected {
+			t.Errorf("ParseExpression(%q) error = %q, want %q", tt.input, err.Error(), tt.expected)
+		}
+	}
+}
+
+func TestParseExpression_Options(t *testing.T) {
+	expression, err := ParseExpression("tag in tags", WithInfixOperator("in", EQUALS, NonAssociative))
+	if err != nil {
+		t.Fatalf("ParseExpression returned %v", err)
+	}
+	if actual := expression.String(); actual != "(tag in tags)" {
+		t.Errorf("ParseExpression = %q, want %q", actual, "(tag in tags)")
+	}
+}
+
+func TestParseStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x = 1", "let x = 1;"},
+		{"let x = 1;", "let x = 1;"},
+		{"return a + b", "return (a + b);"},
+		{"for x in xs { x }", "for x in xs { x }"},
+		{"f(x)\n", "f(x)"},
+	}
+
+	for _, tt := range tests {
+		statement, err := ParseStatement(tt.input)
+		if err != nil {
+			t.Errorf("ParseStatement(%q) returned %v", tt.input, err)
+			continue
+		}
+		if actual := statement.String(); actual != tt.expected {
+			t.Errorf("ParseStatement(%q) = %q, want %q", tt.input, actual, tt.expected)
+		}
+	}
+
+	if statement, _ := ParseStatement("let x = 1"); statement != nil {
+		if _, ok := statement.(*statements.Assign); !ok {
+			t.Errorf("ParseStatement returned %T, want *statements.Assign", statement)
+		}
+	}
+}
+
+func TestParseStatement_Errors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x = 1; let y = 2;", "1:12: error[P0013]: expected end of input, got Let instead"},
+		{"let x = 1\nx", "2:1: error[P0013]: expected end of input, got Ident instead"},
+		{"let x 1", "1:7: error[P0001]: expected next token to be Assign, got Number instead"},
+	}
+
+	for _, tt := range tests {
+		statement, err := ParseStatement(tt.input)
+		if err == nil {
+			t.Errorf("ParseStatement(%q) = %v, want an error", tt.input, statement)
+			continue
+		}
+		if statement != nil {
+			t.Errorf("ParseStatement(%q) returned %v alongside an error", tt.input, statement)
+		}
+		if err.Error() != tt.expected {
+			t.Errorf("ParseStatement(%q) error = %q, want %q", tt.input, err.Error(), tt.expected)
+		}
+	}
+}
+
+func TestParseString(t *testing.T) {
+	program, err := ParseString("let x = 1\nx + 1")
+	if err != nil {
+		t.Fatalf("ParseString returned %v", err)
+	}
+	if actual := program.String(); actual != "let x = 1;(x + 1)" {
+		t.Errorf("ParseString = %q, want %q", actual, "let x = 1;(x + 1)")
+	}
+
+	program, err = ParseString("let x 1\ny")
+	if err == nil || err.Error() != "1:7: error[P0001]: expected next token to be Assign, got Number instead" {
+		t.Errorf("ParseString error = %v", err)
+	}
+	if program == nil || program.String() != "<error>y" {
+		t.Errorf("expected a partial program alongside the error, got %v", program)
+	}
+}
+
+func TestParseString_NUL(t *testing.T) {
+	program, err := ParseString("let a = 1\x00let b = 2")
+	expected := "1:10: error[L0002]: invalid character '\\x00'"
+	if err == nil || err.Error() != expected {
+		t.Errorf("ParseString error = %v, want %s", err, expected)
+	}
+	if program == nil || len(program.Statements) != 2 {
+		t.Errorf("expected lexing to continue past the NUL, got %v", program)
+	}
+}
+
+func TestParseString_LexerOptions(t *testing.T) {
+	_, err := ParseString("\tlet x 1", WithLexerOptions(lexer.WithTabWidth(4)))
+	expected := "1:11: error[P0001]: expected next token to be Assign, got Number instead"
+	if err == nil || err.Error() != expected {
+		t.Errorf("ParseString error = %v, want %s", err, expected)
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	program, err := ParseFile("testdata/valid.lang")
+	if err != nil {
+		t.Fatalf("ParseFile returned %v", err)
+	}
+	if actual := program.String(); actual != "let limit = 10;let ratio = (limit ** 2);" {
+		t.Errorf("ParseFile = %q", actual)
+	}
+	if pos := program.Statements[1].(*statements.Assign).Pattern.(*patterns.Identifier).Name.Token.Start; pos.String() != "testdata/valid.lang:2:5" {
+		t.Errorf("expected positions to name the file, got %s", pos)
+	}
+
+	_, err = ParseFile("testdata/broken.lang")
+	expected := "testdata/broken.lang:2:11: error[P0001]: expected next token to be Assign, got Number instead"
+	if err == nil || err.Error() != expected {
+		t.Errorf("ParseFile error = %v, want %s", err, expected)
+	}
+
+	if _, err := ParseFile("testdata/missing.lang"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ParseFile error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestParseFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"rules/config.lang": {Data: []byte("let a = 1\n\nlet b = a +\n  # 2\n")},
+	}
+
+	_, err := ParseFS(fsys, "rules/config.lang")
+	var list diag.List
+	if !errors.As(err, &list) || len(list) == 0 {
+		t.Fatalf("ParseFS error = %v, want a diag.List", err)
+	}
+	if actual := list[0].Error(); actual != "rules/config.lang:4:3: error[L0002]: invalid character '#'" {
+		t.Errorf("first diagnostic = %q", actual)
+	}
+	if actual := list[0].Span.String(); actual != "rules/config.lang:4:3-4:4" {
+		t.Errorf("span = %q", actual)
+	}
+
+	_, err = ParseFS(fsys, "rules/config.lang", WithLexerOptions(lexer.WithFilename("config.lang")))
+	if !errors.As(err, &list) || len(list) == 0 || list[0].Span.String() != "config.lang:4:3-4:4" {
+		t.Errorf("expected the lexer options to override the file name, got %v", err)
+	}
+
+	if _, err := ParseFS(fsys, "missing.lang"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ParseFS error = %v, want fs.ErrNotExist", err)
+	}
+}
@@ -1,23 +1,40 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"lang/ast/expressions"
+	"lang/ast/patterns"
 	"lang/ast/statements"
+	"lang/diag"
 	"lang/lexer"
 	"lang/lexer/token"
+	"math/big"
 	"strconv"
+	"strings"
 )
 
 const (
 	_ int = iota
 	LOWEST
+	ASSIGN       // X = Y
+	CONDITIONAL  // X ? Y : Z
+	NULLISH      // ??
+	LOGICAL_OR   // ||
+	LOGICAL_AND  // &&
+	BIT_OR       // |
+	BIT_XOR      // ^
+	BIT_AND      // &
 	EQUALS       // ==
 	LESS_GREATER // > or <
+	SHIFT        // << or >>
 	SUM          // +
 	PRODUCT      // *
-	PREFIX       // -X or !X
-	CALL         // myFunction(X)
+	POWER        // **
+	PREFIX       // custom prefix operators binding tighter than **
+	CALL         // myFunction(X) or X.Y
 )
 
 type (
@@ -26,21 +43,45 @@ type (
 )
 
 type Parser struct {
-	l              *lexer.Lexer
-	currentToken   token.Token
-	peekToken      token.Token
-	errors         []string
+	l            *lexer.Lexer
+	currentToken token.Token
+	peekToken    token.Token
+	diagnostics  diag.List
+	// panicking is set from the first error in a statement until the parser
+	// has resynchronised, and suppresses the follow-on errors in between.
+	panicking bool
+	maxErrors int
+	errors    int
+	// stopped is set once maxErrors has been reached.
+	stopped bool
+	// blockDepth counts the blocks being parsed, so that recovery only stops
+	// at a } that can close one.
+	blockDepth     int
 	prefixParseFns map[token.Type]prefixParseFn
 	infixParseFns  map[token.Type]infixParseFn
+	// prefixOperators, infixOperators and postfixOperators hold the operators
+	// registered through options, keyed by spelling.
+	prefixOperators  map[string]int
+	infixOperators   map[string]operatorInfo
+	postfixOperators map[string]operatorInfo
+	// tracer receives the trace enabled by WithTrace, indented by traceDepth.
+	tracer     io.Writer
+	traceDepth int
+	// arrows caches isArrowFunction by the offset of each (, so nested
+	// parentheses are only scanned once.
+	arrows map[int]bool
 }
 
-func New(l *lexer.Lexer) *Parser {
+func New(l *lexer.Lexer, opts ...Option) *Parser {
 	p := &Parser{l: l}
+	for _, opt := range opts {
+		opt(p)
+	}
 
 	p.nextToken()
 	p.nextToken()
 
-	p.errors = []string{}
+	p.diagnostics = diag.List{}
 
 	p.prefixParseFns = make(map[token.Type]prefixParseFn)
 	p.infixParseFns = make(map[token.Type]infixParseFn)
@@ -49,6 +90,9 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.Number, p.ParseNumberLiteral)
 	p.registerPrefix(token.Minus, p.parsePrefixExpression)
 	p.registerPrefix(token.Not, p.parsePrefixExpression)
+	p.registerPrefix(token.BitNot, p.parsePrefixExpression)
+	p.registerPrefix(token.LParen, p.parseParenthesisedExpression)
+	p.registerPrefix(token.Function, p.parseFunctionLiteral)
 	p.registerInfix(token.Plus, p.parseInfixExpression)
 	p.registerInfix(token.Minus, p.parseInfixExpression)
 	p.registerInfix(token.Multiply, p.parseInfixExpression)
@@ -59,6 +103,21 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.GreaterThan, p.parseInfixExpression)
 	p.registerInfix(token.LessThanOrEqual, p.parseInfixExpression)
 	p.registerInfix(token.GreaterThanOrEqual, p.parseInfixExpression)
+	p.registerInfix(token.Percent, p.parseInfixExpression)
+	p.registerInfix(token.Power, p.parseInfixExpression)
+	p.registerInfix(token.And, p.parseInfixExpression)
+	p.registerInfix(token.Or, p.parseInfixExpression)
+	p.registerInfix(token.NullishCoalescing, p.parseInfixExpression)
+	p.registerInfix(token.BitAnd, p.parseInfixExpression)
+	p.registerInfix(token.BitOr, p.parseInfixExpression)
+	p.registerInfix(token.BitXor, p.parseInfixExpression)
+	p.registerInfix(token.ShiftLeft, p.parseInfixExpression)
+	p.registerInfix(token.ShiftRight, p.parseInfixExpression)
+	p.registerInfix(token.Question, p.parseConditionalExpression)
+	p.registerInfix(token.Assign, p.parseAssignExpression)
+	p.registerInfix(token.FullStop, p.parseMemberExpression)
+	p.registerInfix(token.OptionalChain, p.parseMemberExpression)
+	p.registerInfix(token.LParen, p.parseCallExpression)
 
 	return p
 }
@@ -71,14 +130,13 @@ func (p *Parser) registerInfix(t token.Type, fn infixParseFn) {
 	p.infixParseFns[t] = fn
 }
 
-func (p *Parser) Errors() []string {
-	return p.errors
-}
-
 func (p *Parser) peekError(t token.Type) {
-	msg := fmt.Sprintf("expected next token to be %s, got %s instead",
+	if p.reportedByLexer(p.peekToken) {
+		return
+	}
+	d := p.errorf(CodeUnexpectedToken, p.peekToken.Span(), "expected next token to be %s, got %s instead",
 		token.GetStringFromTokenType(t), token.GetStringFromTokenType(p.peekToken.Type))
-	p.errors = append(p.errors, msg)
+	p.suggestKeyword(d, p.peekToken)
 }
 
 func (p *Parser) nextToken() {
@@ -89,81 +147,440 @@ func (p *Parser) nextToken() {
 func (p *Parser) ParseProgram() *statements.Program {
 	program := &statements.Program{}
 	program.Statements = []statements.Statement{}
+	for stmt := range p.Statements() {
+		program.Statements = append(program.Statements, stmt)
+	}
+
+	return program
+}
+
+// Statements returns an iterator that parses and yields one statement at a
+// time, so that a program read from a continuous stream can be processed
+// without holding all of it in memory. Each statement is yielded as soon as
+// it is complete and the single token of lookahead after it has been read.
+func (p *Parser) Statements() iter.Seq[statements.Statement] {
+	return func(yield func(statements.Statement) bool) {
+		for !p.curTokenIs(token.Eof) && !p.stopped {
+			stmt := p.parseStatementWithRecovery()
+			// No lookahead crosses a statement boundary, so the cached
+			// arrow function lookaheads are no longer needed.
+			clear(p.arrows)
+			if stmt != nil && !yield(stmt) {
+				return
+			}
+			p.nextToken()
+		}
+	}
+}
+
+// parseStatementWithRecovery parses a statement and, if it reported an
+// error, skips to the end of the statement and returns an ErrorNode in its
+// place.
+func (p *Parser) parseStatementWithRecovery() statements.Statement {
+	start := p.currentToken
+	stmt := p.parseStatement()
+
+	if !p.panicking {
+		return stmt
+	}
+
+	p.synchronize()
+	return &statements.ErrorNode{
+		Token: start,
+		Span:  token.Span{Start: start.Start, End: p.currentToken.End},
+	}
+}
+
+// synchronize skips tokens until the current token ends a statement: a
+// semicolon, or the last token before a } closing an open block, a line
+// break, the end of input or a keyword that starts a new statement. Braces
+// opened by the skipped tokens are skipped as a whole, so that recovery does
+// not stop at their } and report it as a second error.
+func (p *Parser) synchronize() {
+	depth := 0
 	for !p.curTokenIs(token.Eof) {
-		stmt := p.parseStatement()
-		if stmt != nil {
-			program.Statements = append(program.Statements, stmt)
+		switch {
+		case p.curTokenIs(token.LBrace):
+			depth++
+		case p.curTokenIs(token.RBrace) && depth > 0:
+			depth--
+		}
+
+		if depth == 0 {
+			if p.curTokenIs(token.Semicolon) {
+				break
+			}
+			if p.peekTokenIs(token.RBrace) && p.blockDepth > 0 || p.peekTokenIs(token.Eof) ||
+				p.peekToken.Start.Line > p.currentToken.End.Line || statementKeywords[p.peekToken.Type] {
+				break
+			}
 		}
 		p.nextToken()
 	}
+	p.panicking = false
+}
 
-	return program
+// statementKeywords are the keywords that can only start a statement, and so
+// mark where parsing can resume after an error.
+var statementKeywords = map[token.Type]bool{
+	token.Let:      true,
+	token.Const:    true,
+	token.Return:   true,
+	token.If:       true,
+	token.For:      true,
+	token.While:    true,
+	token.Do:       true,
+	token.Break:    true,
+	token.Continue: true,
+	token.Try:      true,
+	token.Switch:   true,
+	token.Throw:    true,
 }
 
 func (p *Parser) parseStatement() statements.Statement {
+	defer p.untrace(p.trace("parseStatement"))
+
+	// Each case checks for nil itself, since a nil *Assign stored in the
+	// Statement interface would not compare equal to nil.
 	switch p.currentToken.Type {
 	case token.Let:
-		return p.parseAssignStatement()
+		if stmt := p.parseAssignStatement(); stmt != nil {
+			return stmt
+		}
 	case token.Return:
-		return p.parseReturnStatement()
+		if stmt := p.parseReturnStatement(); stmt != nil {
+			return stmt
+		}
+	case token.For:
+		if stmt := p.parseForInStatement(); stmt != nil {
+			return stmt
+		}
 	default:
-		return p.parseExpressionStatement()
+		if stmt := p.parseExpressionStatement(); stmt != nil {
+			return stmt
+		}
 	}
+	return nil
 }
 
 func (p *Parser) parseAssignStatement() *statements.Assign {
+	defer p.untrace(p.trace("parseAssignStatement"))
+
 	stmt := &statements.Assign{Token: p.currentToken}
 
-	if !p.expectPeek(token.Ident) {
+	if p.peekTokenIs(token.LBracket) || p.peekTokenIs(token.LBrace) {
+		p.nextToken()
+		stmt.Pattern = p.parsePattern()
+		if stmt.Pattern == nil {
+			return nil
+		}
+	} else {
+		if !p.expectPeekIdentifier() {
+			return nil
+		}
+		name := &expressions.Identifier{Token: p.currentToken, Value: p.currentToken.Value}
+		stmt.Pattern = &patterns.Identifier{Name: name}
+	}
+
+	if !p.expectPeek(token.Assign) {
 		return nil
 	}
 
-	stmt.Name = &expressions.Identifier{Token: p.currentToken, Value: p.currentToken.Value}
+	p.nextToken()
+	valueStart := p.currentToken
+	stmt.Value = p.parseExpression(LOWEST)
+	if stmt.Value == nil {
+		return nil
+	}
 
-	if !p.expectPeek(token.Assign) {
+	p.expectStatementEnd(valueStart)
+
+	return stmt
+}
+
+// parseForInStatement parses for <pattern> in <expression> { ... }, where the
+// pattern is a single name or a destructuring pattern.
+func (p *Parser) parseForInStatement() *statements.ForIn {
+	defer p.untrace(p.trace("parseForInStatement"))
+
+	stmt := &statements.ForIn{Token: p.currentToken}
+
+	p.nextToken()
+	stmt.Binding = p.parsePattern()
+	if stmt.Binding == nil {
 		return nil
 	}
 
-	for !p.curTokenIs(token.Semicolon) {
-		p.nextToken()
+	if !p.expectPeek(token.In) {
+		return nil
+	}
+
+	p.nextToken()
+	stmt.Iterable = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.LBrace) {
+		return nil
 	}
+	stmt.Body = p.parseBlockStatement()
 
 	return stmt
 }
 
-func (p *Parser) parseReturnStatement() *statements.Return {
-	stmt := &statements.Return{Token: p.currentToken}
+// parsePattern parses a binding target starting at the current token: a name,
+// an array pattern or an object pattern. It returns nil on error.
+func (p *Parser) parsePattern() patterns.Pattern {
+	defer p.untrace(p.trace("parsePattern"))
+
+	switch {
+	case p.curTokenIs(token.LBracket):
+		return p.parseArrayPattern()
+	case p.curTokenIs(token.LBrace):
+		return p.parseObjectPattern()
+	case p.curTokenIs(token.Ident) || p.l.Dialect().IsContextual(p.currentToken.Type):
+		return &patterns.Identifier{Name: &expressions.Identifier{Token: p.currentToken, Value: p.currentToken.Value}}
+	default:
+		p.errorf(CodeInvalidPattern, p.currentToken.Span(), "expected a name or destructuring pattern, got %s instead",
+			token.GetStringFromTokenType(p.currentToken.Type))
+		return nil
+	}
+}
+
+// parsePatternElement parses an element of an array or object pattern, which
+// unlike a top-level pattern may have a default value.
+func (p *Parser) parsePatternElement() patterns.Pattern {
+	pattern := p.parsePattern()
+	if pattern == nil {
+		return nil
+	}
 
+	if p.peekTokenIs(token.Assign) {
+		id, ok := pattern.(*patterns.Identifier)
+		if !ok {
+			p.errorf(CodeInvalidDefault, p.peekToken.Span(), "nested pattern %s cannot have a default value", pattern)
+			return nil
+		}
+		p.nextToken()
+		p.nextToken()
+		id.Default = p.parseExpression(LOWEST)
+	}
+
+	return pattern
+}
+
+// parseRestElement parses the name after a ... in a pattern, which must be
+// the last element before closing.
+func (p *Parser) parseRestElement(closing token.Type) *expressions.Identifier {
 	p.nextToken()
+	if !p.expectPeekIdentifier() {
+		return nil
+	}
+	rest := &expressions.Identifier{Token: p.currentToken, Value: p.currentToken.Value}
+
+	if !p.peekTokenIs(closing) {
+		p.errorf(CodeMisplacedRest, p.currentToken.Span(), "rest element %s must be the last element", rest)
+		return nil
+	}
+
+	return rest
+}
+
+func (p *Parser) parseArrayPattern() patterns.Pattern {
+	defer p.untrace(p.trace("parseArrayPattern"))
+
+	pattern := &patterns.Array{Token: p.currentToken}
+
+	for !p.peekTokenIs(token.RBracket) {
+		if p.peekTokenIs(token.Ellipsis) {
+			pattern.Rest = p.parseRestElement(token.RBracket)
+			if pattern.Rest == nil {
+				return nil
+			}
+			break
+		}
 
-	for !p.curTokenIs(token.Semicolon) {
 		p.nextToken()
+		element := p.parsePatternElement()
+		if element == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, element)
+
+		if !p.peekTokenIs(token.Comma) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek(token.RBracket) {
+		return nil
+	}
+
+	return pattern
+}
+
+func (p *Parser) parseObjectPattern() patterns.Pattern {
+	defer p.untrace(p.trace("parseObjectPattern"))
+
+	pattern := &patterns.Object{Token: p.currentToken}
+
+	for !p.peekTokenIs(token.RBrace) {
+		if p.peekTokenIs(token.Ellipsis) {
+			pattern.Rest = p.parseRestElement(token.RBrace)
+			if pattern.Rest == nil {
+				return nil
+			}
+			break
+		}
+
+		if !p.expectPeekIdentifier() {
+			return nil
+		}
+		property := &patterns.Property{Key: &expressions.Identifier{Token: p.currentToken, Value: p.currentToken.Value}}
+
+		// In the shorthand { x } the key is also the name being bound.
+		if p.peekTokenIs(token.Colon) {
+			p.nextToken()
+			p.nextToken()
+		}
+		property.Value = p.parsePatternElement()
+		if property.Value == nil {
+			return nil
+		}
+		pattern.Properties = append(pattern.Properties, property)
+
+		if !p.peekTokenIs(token.Comma) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek(token.RBrace) {
+		return nil
 	}
 
+	return pattern
+}
+
+func (p *Parser) parseReturnStatement() *statements.Return {
+	defer p.untrace(p.trace("parseReturnStatement"))
+
+	stmt := &statements.Return{Token: p.currentToken}
+
+	if p.atStatementEnd() {
+		p.expectStatementEnd(p.currentToken)
+		return stmt
+	}
+
+	p.nextToken()
+	valueStart := p.currentToken
+	stmt.ReturnValue = p.parseExpression(LOWEST)
+	if stmt.ReturnValue == nil {
+		return nil
+	}
+
+	p.expectStatementEnd(valueStart)
+
 	return stmt
 }
 
 func (p *Parser) parseExpressionStatement() *statements.ExpressionStatement {
+	defer p.untrace(p.trace("parseExpressionStatement"))
+
 	stmt := &statements.ExpressionStatement{Token: p.currentToken}
 
 	stmt.Expression = p.parseExpression(LOWEST)
-
-	if p.peekTokenIs(token.Semicolon) {
-		p.nextToken()
+	if stmt.Expression == nil {
+		return nil
 	}
 
+	p.expectStatementEnd(stmt.Token)
+
 	return stmt
 }
 
+// atStatementEnd reports whether the statement being parsed ends after the
+// current token, at a semicolon, a closing }, the end of input or a line break.
+func (p *Parser) atStatementEnd() bool {
+	return p.peekTokenIs(token.Semicolon) || p.peekTokenIs(token.RBrace) ||
+		p.peekTokenIs(token.Eof) || p.newlineEndsStatement()
+}
+
+// newlineEndsStatement reports whether a line break between the current and
+// peek tokens ends the statement. As in Go, it does when the line ends with a
+// token that can end a statement: a name, a literal, return, break, continue
+// or a closing bracket. A line ending in an operator or comma continues onto
+// the next.
+func (p *Parser) newlineEndsStatement() bool {
+	if p.peekToken.Start.Line <= p.currentToken.End.Line {
+		return false
+	}
+
+	switch p.currentToken.Type {
+	case token.Ident, token.Number, token.String, token.True, token.False,
+		token.Return, token.Break, token.Continue,
+		token.RParen, token.RBracket, token.RBrace:
+		return true
+	default:
+		return p.l.Dialect().IsContextual(p.currentToken.Type)
+	}
+}
+
+// expectStatementEnd consumes the semicolon ending a statement, if there is
+// one, and reports an error if the statement is not terminated. A line break
+// is rejected as a terminator when the next line starts with a token that
+// could also continue the expression, such as ( or -, since the reader cannot
+// tell which was meant.
+//
+// exprStart is the first token of the expression that ends the statement.
+// When the statement runs on, that token is often a misspelled keyword, as in
+// retrun x, so it is checked for a suggestion.
+func (p *Parser) expectStatementEnd(exprStart token.Token) {
+	switch {
+	case p.peekTokenIs(token.Semicolon):
+		p.nextToken()
+	case p.peekTokenIs(token.RBrace), p.peekTokenIs(token.Eof):
+	case p.newlineEndsStatement():
+		t := p.peekToken.Type
+		if p.prefixParseFn(p.peekToken) != nil && p.infixParseFn(p.peekToken) != nil {
+			p.errorf(CodeAmbiguousLineBreak, p.peekToken.Span(),
+				"ambiguous line break before %s on line %d: end the previous line with ; or join the lines",
+				token.GetStringFromTokenType(t), p.peekToken.Start.Line).
+				WithLabel(p.currentToken.Span(), "the previous statement ends here").
+				WithFix("end the previous statement", insertionAfter(p.currentToken), ";")
+		}
+	default:
+		if p.reportedByLexer(p.peekToken) {
+			return
+		}
+		d := p.errorf(CodeMissingTerminator, p.peekToken.Span(), "expected ; or a line break after statement, got %s instead",
+			token.GetStringFromTokenType(p.peekToken.Type))
+		p.suggestKeyword(d, exprStart)
+		d.WithFix("end the statement", insertionAfter(p.currentToken), ";")
+	}
+}
+
 func (p *Parser) parseExpression(precedence int) expressions.Expression {
-	prefix := p.prefixParseFns[p.currentToken.Type]
+	defer p.untrace(p.trace("parseExpression"))
+
+	if p.tracer != nil {
+		p.tracef("precedence %s", precedenceName(precedence))
+	}
+	prefix := p.prefixParseFn(p.currentToken)
 	if prefix == nil {
 		p.noPrefixParseFnError(p.currentToken.Type)
 		return nil
 	}
 	leftExp := prefix()
-	for !p.peekTokenIs(token.Semicolon) && precedence < p.peekPrecedence() {
-		infix := p.infixParseFns[p.peekToken.Type]
+	for !p.peekTokenIs(token.Semicolon) && !p.newlineEndsStatement() {
+		peekPrecedence := p.peekPrecedence()
+		if p.tracer != nil {
+			p.tracef("compare peek %s (%s) with %s: continue=%t",
+				traceToken(p.peekToken), precedenceName(peekPrecedence), precedenceName(precedence), precedence < peekPrecedence)
+		}
+		if precedence >= peekPrecedence {
+			break
+		}
+		infix := p.infixParseFn(p.peekToken)
 		if infix == nil {
 			return leftExp
 		}
@@ -174,30 +591,105 @@ func (p *Parser) parseExpression(precedence int) expressions.Expression {
 }
 
 func (p *Parser) parseIdentifier() expressions.Expression {
-	return &expressions.Identifier{Token: p.currentToken, Value: p.currentToken.Value}
+	defer p.untrace(p.trace("parseIdentifier"))
+
+	ident := &expressions.Identifier{Token: p.currentToken, Value: p.currentToken.Value}
+
+	// A lone identifier followed by => is the parameter of a concise arrow
+	// function, as in x => x * 2.
+	if p.peekTokenIs(token.Arrow) {
+		fn := &expressions.FunctionLiteral{
+			Token:      p.currentToken,
+			Parameters: []*expressions.Parameter{{Name: ident}},
+			Arrow:      true,
+		}
+		p.nextToken()
+		return p.parseArrowBody(fn)
+	}
+
+	return ident
 }
 
 func (p *Parser) ParseNumberLiteral() expressions.Expression {
+	defer p.untrace(p.trace("ParseNumberLiteral"))
+
 	tokenValue := p.currentToken.Value
+	digits := strings.ReplaceAll(tokenValue, "_", "")
 
-	if i, err := strconv.ParseInt(tokenValue, 10, 64); err == nil {
-		return &expressions.NumberLiteral[int64]{
-			Token: p.currentToken,
-			Value: i,
+	if base, ok := numberBase(digits); ok {
+		return p.parseIntegerLiteral(digits[2:], base)
+	}
+
+	if strings.HasSuffix(digits, "m") {
+		if r, ok := new(big.Rat).SetString(strings.TrimSuffix(digits, "m")); ok {
+			return &expressions.NumberLiteral[*big.Rat]{
+				Token: p.currentToken,
+				Value: r,
+			}
 		}
+
+		p.errorf(CodeInvalidNumber, p.currentToken.Span(), "could not parse %q as number", tokenValue)
+		return nil
 	}
 
-	if f, err := strconv.ParseFloat(tokenValue, 64); err == nil {
+	if !strings.ContainsAny(digits, ".eE") {
+		return p.parseIntegerLiteral(digits, 10)
+	}
+
+	if f, err := strconv.ParseFloat(digits, 64); err == nil {
 		return &expressions.NumberLiteral[float64]{
 			Token: p.currentToken,
 			Value: f,
 		}
 	}
 
-	p.errors = append(p.errors, fmt.Sprintf("could not parse %q as number", tokenValue))
+	p.errorf(CodeInvalidNumber, p.currentToken.Span(), "could not parse %q as number", tokenValue)
+	return nil
+}
+
+// parseIntegerLiteral parses digits in the given base, falling back to a
+// *big.Int when the value does not fit in an int64 rather than losing
+// precision.
+func (p *Parser) parseIntegerLiteral(digits string, base int) expressions.Expression {
+	i, err := strconv.ParseInt(digits, base, 64)
+	if err == nil {
+		return &expressions.NumberLiteral[int64]{
+			Token: p.currentToken,
+			Value: i,
+		}
+	}
+
+	if errors.Is(err, strconv.ErrRange) {
+		if b, ok := new(big.Int).SetString(digits, base); ok {
+			return &expressions.NumberLiteral[*big.Int]{
+				Token: p.currentToken,
+				Value: b,
+			}
+		}
+	}
+
+	p.errorf(CodeInvalidNumber, p.currentToken.Span(), "could not parse %q as number", p.currentToken.Value)
 	return nil
 }
 
+// numberBase reports the base of a prefixed integer literal such as 0xFF.
+func numberBase(literal string) (int, bool) {
+	if len(literal) < 2 || literal[0] != '0' {
+		return 0, false
+	}
+
+	switch literal[1] {
+	case 'x', 'X':
+		return 16, true
+	case 'o', 'O':
+		return 8, true
+	case 'b', 'B':
+		return 2, true
+	default:
+		return 0, false
+	}
+}
+
 func (p *Parser) curTokenIs(t token.Type) bool {
 	return p.currentToken.Type == t
 }
@@ -216,56 +708,429 @@ func (p *Parser) expectPeek(t token.Type) bool {
 	}
 }
 
+// peekIsName reports whether the next token can be used as a name: an
+// identifier or a contextual keyword.
+func (p *Parser) peekIsName() bool {
+	return p.peekTokenIs(token.Ident) || p.l.Dialect().IsContextual(p.peekToken.Type)
+}
+
+// expectPeekIdentifier is like expectPeek(token.Ident), but also accepts
+// contextual keywords, which may be used as names.
+func (p *Parser) expectPeekIdentifier() bool {
+	if p.l.Dialect().IsContextual(p.peekToken.Type) {
+		p.nextToken()
+		return true
+	}
+	return p.expectPeek(token.Ident)
+}
+
 func (p *Parser) noPrefixParseFnError(t token.Type) {
-	msg := fmt.Sprintf("no prefix parse function for %s found", token.GetStringFromTokenType(t))
-	p.errors = append(p.errors, msg)
+	if p.reportedByLexer(p.currentToken) {
+		return
+	}
+	p.errorf(CodeExpectedExpression, p.currentToken.Span(), "no prefix parse function for %s found",
+		token.GetStringFromTokenType(t))
 }
 
 func (p *Parser) parsePrefixExpression() expressions.Expression {
+	defer p.untrace(p.trace("parsePrefixExpression"))
+
 	expression := &expressions.PrefixExpression{
 		Token:    p.currentToken,
 		Operator: p.currentToken.Value,
 	}
+	precedence := p.prefixPrecedence(p.currentToken)
 	p.nextToken()
-	expression.Right = p.parseExpression(PREFIX)
+	expression.Right = p.parseExpression(precedence)
 	return expression
 }
 
 func (p *Parser) parseInfixExpression(left expressions.Expression) expressions.Expression {
+	defer p.untrace(p.trace("parseInfixExpression"))
+
 	expression := &expressions.InfixExpression{
 		Token:    p.currentToken,
 		Left:     left,
 		Operator: p.currentToken.Value,
 	}
 	precedence := p.curPrecedence()
+	associativity := p.associativity(p.currentToken)
 	p.nextToken()
-	expression.Right = p.parseExpression(precedence)
+	expression.Right = p.parseExpression(operandPrecedence(precedence, associativity))
+
+	if associativity == NonAssociative && p.peekPrecedence() == precedence {
+		d := p.errorf(CodeNonAssociative, p.peekToken.Span(), "%s cannot be chained with %s, add parentheses to group them",
+			expression.Operator, p.peekToken.Value).
+			WithLabel(expression.Token.Span(), "first operator here")
+		if precedence == EQUALS || precedence == LESS_GREATER {
+			d.WithNote(fmt.Sprintf("comparisons do not chain as in mathematics; to test both, write %s %s %s && %s %s ...",
+				expression.Left, expression.Operator, expression.Right, expression.Right, p.peekToken.Value))
+		}
+		return nil
+	}
+
+	return expression
+}
+
+// parseAssignExpression parses an assignment, which is right-associative so
+// that a = b = c assigns c to b and then to a.
+func (p *Parser) parseAssignExpression(target expressions.Expression) expressions.Expression {
+	defer p.untrace(p.trace("parseAssignExpression"))
+
+	switch target.(type) {
+	case *expressions.Identifier, *expressions.MemberExpression:
+	default:
+		p.errorf(CodeInvalidAssignmentTarget, p.currentToken.Span(), "cannot assign to %s", target)
+		return nil
+	}
+
+	expression := &expressions.AssignExpression{Token: p.currentToken, Target: target}
+	precedence := p.curPrecedence()
+	associativity := p.associativity(p.currentToken)
+	p.nextToken()
+	expression.Value = p.parseExpression(operandPrecedence(precedence, associativity))
+
+	return expression
+}
+
+func (p *Parser) parseConditionalExpression(condition expressions.Expression) expressions.Expression {
+	defer p.untrace(p.trace("parseConditionalExpression"))
+
+	expression := &expressions.ConditionalExpression{
+		Token:     p.currentToken,
+		Condition: condition,
+	}
+	p.nextToken()
+	expression.Consequence = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.Colon) {
+		return nil
+	}
+
+	// Parsing the alternative at the lowest precedence makes conditionals
+	// right-associative: a ? b : c ? d : e is a ? b : (c ? d : e).
+	p.nextToken()
+	expression.Alternative = p.parseExpression(LOWEST)
+	return expression
+}
+
+func (p *Parser) parseMemberExpression(object expressions.Expression) expressions.Expression {
+	defer p.untrace(p.trace("parseMemberExpression"))
+
+	expression := &expressions.MemberExpression{
+		Token:    p.currentToken,
+		Object:   object,
+		Optional: p.curTokenIs(token.OptionalChain),
+	}
+
+	if !p.expectPeekIdentifier() {
+		return nil
+	}
+
+	expression.Property = &expressions.Identifier{Token: p.currentToken, Value: p.currentToken.Value}
 	return expression
 }
 
 var precedences = map[token.Type]int{
+	token.Assign:             ASSIGN,
+	token.Question:           CONDITIONAL,
+	token.NullishCoalescing:  NULLISH,
+	token.Or:                 LOGICAL_OR,
+	token.And:                LOGICAL_AND,
+	token.BitOr:              BIT_OR,
+	token.BitXor:             BIT_XOR,
+	token.BitAnd:             BIT_AND,
 	token.Equal:              EQUALS,
 	token.NotEqual:           EQUALS,
 	token.LessThan:           LESS_GREATER,
 	token.GreaterThan:        LESS_GREATER,
 	token.LessThanOrEqual:    LESS_GREATER,
 	token.GreaterThanOrEqual: LESS_GREATER,
+	token.ShiftLeft:          SHIFT,
+	token.ShiftRight:         SHIFT,
 	token.Plus:               SUM,
 	token.Minus:              SUM,
 	token.Multiply:           PRODUCT,
 	token.Divide:             PRODUCT,
+	token.Percent:            PRODUCT,
+	token.Power:              POWER,
+	token.LParen:             CALL,
+	token.FullStop:           CALL,
+	token.OptionalChain:      CALL,
 }
 
 func (p *Parser) curPrecedence() int {
-	if p, ok := precedences[p.currentToken.Type]; ok {
-		return p
-	}
-	return LOWEST
+	return p.precedence(p.currentToken)
 }
 
 func (p *Parser) peekPrecedence() int {
-	if p, ok := precedences[p.peekToken.Type]; ok {
-		return p
+	return p.precedence(p.peekToken)
+}
+
+// parserState is a snapshot of the parser used to back out of speculative
+// parsing.
+type parserState struct {
+	mark         lexer.Mark
+	currentToken token.Token
+	peekToken    token.Token
+	diagnostics  int
+	errors       int
+	panicking    bool
+}
+
+func (p *Parser) saveState() parserState {
+	return parserState{
+		mark:         p.l.Mark(),
+		currentToken: p.currentToken,
+		peekToken:    p.peekToken,
+		diagnostics:  len(p.diagnostics),
+		errors:       p.errors,
+		panicking:    p.panicking,
 	}
-	return LOWEST
+}
+
+// restoreState returns the parser to the saved state and releases it.
+func (p *Parser) restoreState(s parserState) {
+	p.l.Reset(s.mark)
+	p.l.Release(s.mark)
+	p.currentToken = s.currentToken
+	p.peekToken = s.peekToken
+	p.diagnostics = p.diagnostics[:s.diagnostics]
+	p.errors = s.errors
+	p.panicking = s.panicking
+}
+
+// parseParenthesisedExpression parses either a grouped expression or the
+// parameter list of an arrow function, which can only be told apart by
+// whether the closing parenthesis is followed by =>.
+func (p *Parser) parseParenthesisedExpression() expressions.Expression {
+	defer p.untrace(p.trace("parseParenthesisedExpression"))
+
+	if p.isArrowFunction() {
+		fn := &expressions.FunctionLiteral{Token: p.currentToken, Arrow: true}
+		fn.Parameters = p.parseFunctionParameters()
+		if fn.Parameters == nil || !p.expectPeek(token.Arrow) {
+			return nil
+		}
+		return p.parseArrowBody(fn)
+	}
+
+	p.nextToken()
+	exp := p.parseExpression(LOWEST)
+	if !p.expectPeek(token.RParen) {
+		return nil
+	}
+	return exp
+}
+
+// isArrowFunction looks ahead from the current ( to its matching ) and
+// reports whether it is followed by =>. The parser's position is unchanged.
+// Every parenthesis closed during the scan is cached, so nested parentheses
+// are scanned once overall rather than once per level.
+func (p *Parser) isArrowFunction() bool {
+	switch p.peekToken.Type {
+	case token.Ident, token.Ellipsis, token.RParen:
+	default:
+		if !p.l.Dialect().IsContextual(p.peekToken.Type) {
+			return false
+		}
+	}
+
+	start := p.currentToken.Start.Offset
+	if arrow, ok := p.arrows[start]; ok {
+		return arrow
+	}
+	if p.arrows == nil {
+		p.arrows = make(map[int]bool)
+	}
+
+	state := p.saveState()
+	defer p.restoreState(state)
+
+	var open []int
+	for !p.curTokenIs(token.Eof) {
+		switch p.currentToken.Type {
+		case token.LParen, token.LBracket, token.LBrace:
+			open = append(open, p.currentToken.Start.Offset)
+		case token.RParen, token.RBracket, token.RBrace:
+			if len(open) == 0 {
+				return false
+			}
+			p.arrows[open[len(open)-1]] = p.peekTokenIs(token.Arrow)
+			open = open[:len(open)-1]
+		}
+		if len(open) == 0 {
+			return p.arrows[start]
+		}
+		p.nextToken()
+	}
+	return false
+}
+
+// parseArrowBody parses the body after the current =>, which is either a
+// block or a single expression.
+func (p *Parser) parseArrowBody(fn *expressions.FunctionLiteral) expressions.Expression {
+	defer p.untrace(p.trace("parseArrowBody"))
+
+	if p.peekTokenIs(token.LBrace) {
+		p.nextToken()
+		fn.Body = p.parseBlockStatement()
+		return fn
+	}
+
+	p.nextToken()
+	body := p.parseExpression(LOWEST)
+	if body == nil {
+		return nil
+	}
+	fn.Body = body
+	return fn
+}
+
+func (p *Parser) parseFunctionLiteral() expressions.Expression {
+	defer p.untrace(p.trace("parseFunctionLiteral"))
+
+	fn := &expressions.FunctionLiteral{Token: p.currentToken}
+
+	if p.peekIsName() {
+		p.nextToken()
+		fn.Name = &expressions.Identifier{Token: p.currentToken, Value: p.currentToken.Value}
+	}
+
+	if !p.expectPeek(token.LParen) {
+		return nil
+	}
+
+	fn.Parameters = p.parseFunctionParameters()
+	if fn.Parameters == nil {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBrace) {
+		return nil
+	}
+
+	fn.Body = p.parseBlockStatement()
+	return fn
+}
+
+// parseFunctionParameters parses a parameter list starting at the current (
+// and ending on the matching ). Parameters may have default values, and the
+// last may be a rest parameter. It returns nil on error.
+func (p *Parser) parseFunctionParameters() []*expressions.Parameter {
+	defer p.untrace(p.trace("parseFunctionParameters"))
+
+	params := []*expressions.Parameter{}
+
+	if p.peekTokenIs(token.RParen) {
+		p.nextToken()
+		return params
+	}
+
+	for {
+		param := &expressions.Parameter{}
+
+		if p.peekTokenIs(token.Ellipsis) {
+			p.nextToken()
+			param.Rest = true
+		}
+
+		if !p.expectPeekIdentifier() {
+			return nil
+		}
+		param.Name = &expressions.Identifier{Token: p.currentToken, Value: p.currentToken.Value}
+
+		if p.peekTokenIs(token.Assign) {
+			if param.Rest {
+				p.errorf(CodeInvalidDefault, p.peekToken.Span(), "rest parameter %s cannot have a default value", param.Name)
+				return nil
+			}
+			p.nextToken()
+			p.nextToken()
+			param.Default = p.parseExpression(LOWEST)
+		}
+
+		params = append(params, param)
+
+		if !p.peekTokenIs(token.Comma) {
+			break
+		}
+		if param.Rest {
+			p.errorf(CodeMisplacedRest, param.Name.Token.Span(), "rest parameter %s must be the last parameter", param.Name)
+			return nil
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek(token.RParen) {
+		return nil
+	}
+
+	return params
+}
+
+func (p *Parser) parseBlockStatement() *statements.Block {
+	defer p.untrace(p.trace("parseBlockStatement"))
+
+	block := &statements.Block{Token: p.currentToken}
+	block.Statements = []statements.Statement{}
+
+	p.blockDepth++
+	defer func() { p.blockDepth-- }()
+
+	p.nextToken()
+
+	for !p.curTokenIs(token.RBrace) && !p.curTokenIs(token.Eof) && !p.stopped {
+		stmt := p.parseStatementWithRecovery()
+		if stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		}
+		p.nextToken()
+	}
+
+	// A block cut short by WithMaxErrors is not reported as unclosed.
+	if !p.curTokenIs(token.RBrace) && !p.stopped {
+		p.errorf(CodeUnclosedBlock, p.currentToken.Span(), "expected } to close block, got %s instead",
+			token.GetStringFromTokenType(p.currentToken.Type)).
+			WithLabel(block.Token.Span(), "block opened here")
+	}
+
+	return block
+}
+
+func (p *Parser) parseCallExpression(function expressions.Expression) expressions.Expression {
+	defer p.untrace(p.trace("parseCallExpression"))
+
+	call := &expressions.CallExpression{Token: p.currentToken, Function: function}
+	call.Arguments = p.parseCallArguments()
+	if call.Arguments == nil {
+		return nil
+	}
+	return call
+}
+
+func (p *Parser) parseCallArguments() []expressions.Expression {
+	defer p.untrace(p.trace("parseCallArguments"))
+
+	args := []expressions.Expression{}
+
+	if p.peekTokenIs(token.RParen) {
+		p.nextToken()
+		return args
+	}
+
+	p.nextToken()
+	args = append(args, p.parseExpression(LOWEST))
+
+	for p.peekTokenIs(token.Comma) {
+		p.nextToken()
+		p.nextToken()
+		args = append(args, p.parseExpression(LOWEST))
+	}
+
+	if !p.expectPeek(token.RParen) {
+		return nil
+	}
+
+	return args
 }
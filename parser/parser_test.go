@@ -2,9 +2,14 @@ package parser
 
 import (
 	"fmt"
+	"io"
 	"lang/ast/expressions"
+	"lang/ast/patterns"
 	"lang/ast/statements"
 	"lang/lexer"
+	"lang/lexer/token"
+	"math/big"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -91,13 +96,19 @@ func ProcessAssignmentStatement(t *testing.T, s statements.Statement, name strin
 		return false
 	}
 
-	if assignmentStatement.Name.Value != name {
-		t.Errorf("AssignmentStatement.Name.Value not '%s'. got=%s", name, assignmentStatement.Name.Value)
+	pattern, ok := assignmentStatement.Pattern.(*patterns.Identifier)
+	if !ok {
+		t.Errorf("AssignmentStatement.Pattern not *patterns.Identifier. got=%T", assignmentStatement.Pattern)
 		return false
 	}
 
-	if assignmentStatement.Name.TokenValue() != name {
-		t.Errorf("s.Name not '%s'. got=%s", name, assignmentStatement.Name)
+	if pattern.Name.Value != name {
+		t.Errorf("AssignmentStatement.Pattern.Name.Value not '%s'. got=%s", name, pattern.Name.Value)
+		return false
+	}
+
+	if pattern.TokenValue() != name {
+		t.Errorf("s.Pattern not '%s'. got=%s", name, pattern)
 		return false
 	}
 
@@ -159,6 +170,14 @@ func TestNumberLiteralExpression(t *testing.T) {
 	}{
 		{"5;", 5, int64(0)},       // Example integer test case
 		{"5.5;", 5.5, float64(0)}, // Example float test case
+		{"0xFF;", 255, int64(0)},
+		{"0o17;", 15, int64(0)},
+		{"0b1010;", 10, int64(0)},
+		{"1_000_000;", 1000000, int64(0)},
+		{"1.5e-3;", 0.0015, float64(0)},
+		{"2e3;", 2000.0, float64(0)},
+		{".5;", 0.5, float64(0)},
+		{".5e3;", 500.0, float64(0)},
 		// Add more test cases as needed
 	}
 
@@ -203,6 +222,49 @@ func TestNumberLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestArbitraryPrecisionNumberLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"99999999999999999999;", "99999999999999999999"},
+		{"0xFFFFFFFFFFFFFFFFFF;", "4722366482869645213695"},
+		{"19.99m;", "1999/100"},
+		{"1_000.5m;", "2001/2"},
+		{".25m;", "1/4"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(strings.NewReader(tt.input))
+		p := New(l)
+		program := p.ParseProgram()
+		checkParseErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program has not enough statements. got=%d", len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*statements.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *statements.ExpressionStatement. got=%T", program.Statements[0])
+		}
+
+		var actual string
+		switch literal := stmt.Expression.(type) {
+		case *expressions.NumberLiteral[*big.Int]:
+			actual = literal.Value.String()
+		case *expressions.NumberLiteral[*big.Rat]:
+			actual = literal.Value.String()
+		default:
+			t.Fatalf("for input '%s', expected an arbitrary-precision literal, got=%T", tt.input, stmt.Expression)
+		}
+
+		if actual != tt.expected {
+			t.Errorf("for input '%s', expected value %s, got=%s", tt.input, tt.expected, actual)
+		}
+	}
+}
+
 func TestParsingPrefixExpressions(t *testing.T) {
 	prefixTests := []struct {
 		input    string
@@ -323,6 +385,16 @@ func TestParsingInfixExpressions(t *testing.T) {
 		{"5 != 5;", 5, "!=", 5},
 		{"5 >= 5;", 5, ">=", 5},
 		{"5 <= 5;", 5, "<=", 5},
+		{"5 % 5;", 5, "%", 5},
+		{"5 ** 5;", 5, "**", 5},
+		{"5 & 5;", 5, "&", 5},
+		{"5 | 5;", 5, "|", 5},
+		{"5 ^ 5;", 5, "^", 5},
+		{"5 << 5;", 5, "<<", 5},
+		{"5 >> 5;", 5, ">>", 5},
+		{"5 && 5;", 5, "&&", 5},
+		{"5 || 5;", 5, "||", 5},
+		{"5 ?? 5;", 5, "??", 5},
 	}
 	for _, tt := range infixTests {
 		l := lexer.New(strings.NewReader(tt.input))
@@ -375,6 +447,26 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 		{"5 > 4 == 3 < 4;", "((5 > 4) == (3 < 4))"},
 		{"5 < 4 != 3 > 4;", "((5 < 4) != (3 > 4))"},
 		{"3 + 4 * 5 == 3 * 1 + 4 * 5;", "((3 + (4 * 5)) == ((3 * 1) + (4 * 5)))"},
+		{"2 ** 3 ** 2;", "(2 ** (3 ** 2))"},
+		{"2 * 3 ** 2;", "(2 * (3 ** 2))"},
+		{"-2 ** 2;", "(-(2 ** 2))"},
+		{"2 ** -3 ** 2;", "(2 ** (-(3 ** 2)))"},
+		{"-a ** b * c;", "((-(a ** b)) * c)"},
+		{"a | b ^ c & d;", "(a | (b ^ (c & d)))"},
+		{"a & b == c;", "(a & (b == c))"},
+		{"1 << 2 + 3;", "(1 << (2 + 3))"},
+		{"a < b << c;", "(a < (b << c))"},
+		{"~a & b;", "((~a) & b)"},
+		{"a || b && c | d;", "(a || (b && (c | d)))"},
+		{"a ?? b || c;", "(a ?? (b || c))"},
+		{"a ? b : c;", "(a ? b : c)"},
+		{"a?.5:b;", "(a ? .5 : b)"},
+		{"a ? b : c ? d : e;", "(a ? b : (c ? d : e))"},
+		{"a ?? b ? c + 1 : d;", "((a ?? b) ? (c + 1) : d)"},
+		{"a.b.c;", "((a.b).c)"},
+		{"a?.b.c;", "((a?.b).c)"},
+		{"-a.b * c;", "((-(a.b)) * c)"},
+		{"a?.b ?? c;", "((a?.b) ?? c)"},
 	}
 	for _, tt := range tests {
 		l := lexer.New(strings.NewReader(tt.input))
@@ -388,3 +480,647 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 		}
 	}
 }
+
+func TestAssociativity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a - b - c;", "((a - b) - c)"},
+		{"a / b * c;", "((a / b) * c)"},
+		{"a && b && c;", "((a && b) && c)"},
+		{"a << b >> c;", "((a << b) >> c)"},
+		{"2 ** 3 ** 2;", "(2 ** (3 ** 2))"},
+		{"2 ** 3 ** 2 ** 1;", "(2 ** (3 ** (2 ** 1)))"},
+		{"(2 ** 3) ** 2;", "((2 ** 3) ** 2)"},
+		{"a = b = c;", "(a = (b = c))"},
+		{"a = b + c * d;", "(a = (b + (c * d)))"},
+		{"a = b ? c : d;", "(a = (b ? c : d))"},
+		{"a.b = c = d.e;", "((a.b) = (c = (d.e)))"},
+		{"x = y => y = 1;", "(x = ((y) => (y = 1)))"},
+		{"a < b == c;", "((a < b) == c)"},
+		{"(a < b) < c;", "((a < b) < c)"},
+		{"a == (b == c);", "(a == (b == c))"},
+	}
+	for _, tt := range tests {
+		l := lexer.New(strings.NewReader(tt.input))
+		p := New(l)
+		program := p.ParseProgram()
+		checkParseErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+
+func TestStatements_Streaming(t *testing.T) {
+	r, w := io.Pipe()
+
+	// Input is written one line ahead of the statements yielded so far. The
+	// parser needs one token of lookahead, but must not wait for more.
+	lines := []string{"let x = 5;\n", "x + 1;\n", "return x;\n"}
+	go func() {
+		_, _ = w.Write([]byte(lines[0] + lines[1]))
+	}()
+	p := New(lexer.New(r))
+
+	var actual []string
+	for stmt := range p.Statements() {
+		actual = append(actual, stmt.TokenValue())
+		if len(actual)+1 < len(lines) {
+			next := lines[len(actual)+1]
+			go func() {
+				_, _ = w.Write([]byte(next))
+			}()
+		} else if len(actual)+1 == len(lines) {
+			go func() {
+				_ = w.Close()
+			}()
+		}
+	}
+	checkParseErrors(t, p)
+
+	expected := []string{"let", "x", "return"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Statements() yielded %q, want %q", actual, expected)
+	}
+}
+
+func TestStatements_ArrowCacheIsPerStatement(t *testing.T) {
+	p := New(lexer.New(strings.NewReader("f((a) + (b));\ng((x) => x);\n(c);")))
+
+	var actual []string
+	for stmt := range p.Statements() {
+		actual = append(actual, stmt.String())
+		if len(p.arrows) != 0 {
+			t.Errorf("after %q, %d arrow lookaheads are still cached", stmt, len(p.arrows))
+		}
+	}
+	checkParseErrors(t, p)
+
+	expected := []string{"f((a + b))", "g(((x) => x))", "c"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Statements() yielded %q, want %q", actual, expected)
+	}
+}
+
+func TestDialects(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		dialect  *token.Dialect
+		expected string
+	}{
+		{
+			name:     "Class is an identifier in the standard dialect",
+			input:    "let class = 5; class + 1;",
+			dialect:  token.Standard,
+			expected: "let class = 5;(class + 1)",
+		},
+		{
+			name:     "Contextual keyword used as a name",
+			input:    "let static = 5; static * 2;",
+			dialect:  token.Full,
+			expected: "let static = 5;(static * 2)",
+		},
+		{
+			name:     "Contextual keyword used as a function name",
+			input:    "function static() {}; static();",
+			dialect:  token.Full,
+			expected: "function static() { }static()",
+		},
+		{
+			name:     "Let is an identifier in the minimal dialect",
+			input:    "let + 1;",
+			dialect:  token.Minimal,
+			expected: "(let + 1)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := lexer.New(strings.NewReader(tt.input), lexer.WithDialect(tt.dialect))
+			p := New(l)
+			program := p.ParseProgram()
+			checkParseErrors(t, p)
+
+			if actual := program.String(); actual != tt.expected {
+				t.Errorf("expected=%q, got=%q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFunctionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"function(a, b) { a + b; };", "function(a, b) { (a + b) }"},
+		{"function add(a, b = 10, ...rest) { a; };", "function add(a, b = 10, ...rest) { a }"},
+		{"function() {};", "function() { }"},
+		{"(a, b) => a + b;", "((a, b) => (a + b))"},
+		{"x => x * 2;", "((x) => (x * 2))"},
+		{"() => 1;", "(() => 1)"},
+		{"(a, b = 1 + 2, ...rest) => { rest; };", "((a, b = (1 + 2), ...rest) => { rest })"},
+		{"a => b => a + b;", "((a) => ((b) => (a + b)))"},
+		{"(a + b) * c;", "((a + b) * c)"},
+		{"((a)) * (b);", "(a * b)"},
+		{"add(1, 2 * 3);", "add(1, (2 * 3))"},
+		{"map(xs, x => x * 2);", "map(xs, ((x) => (x * 2)))"},
+		{"map(xs, (x, i) => x * i);", "map(xs, ((x, i) => (x * i)))"},
+		{"a.b(c)(d);", "(a.b)(c)(d)"},
+		{"(x => x)(1);", "((x) => x)(1)"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(strings.NewReader(tt.input))
+		p := New(l)
+		program := p.ParseProgram()
+		checkParseErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("for input %q, program has %d statements, want 1", tt.input, len(program.Statements))
+		}
+
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+
+func TestFunctionParsing_DeepNesting(t *testing.T) {
+	const depth = 20000
+
+	tests := []struct {
+		name  string
+		open  string
+		inner string
+	}{
+		{"grouping", "(", "x"},
+		{"binary operands", "(a + ", "x"},
+		{"arrow innermost", "(a + ", "(x) => x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := strings.Repeat(tt.open, depth) + tt.inner + strings.Repeat(")", depth) + ";"
+			l := lexer.New(strings.NewReader(input))
+			p := New(l)
+			program := p.ParseProgram()
+			checkParseErrors(t, p)
+
+			if len(program.Statements) != 1 {
+				t.Fatalf("program has %d statements, want 1", len(program.Statements))
+			}
+		})
+	}
+}
+
+func TestFunctionParameterErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"function(...rest, a) {};", "rest parameter rest must be the last parameter"},
+		{"(...rest = 1) => rest;", "rest parameter rest cannot have a default value"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(strings.NewReader(tt.input))
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("for input %q, expected first error %q, got %q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, b, ...rest] = arr;", "let [a, b, ...rest] = arr;"},
+		{"let { x, y: alias } = obj;", "let { x, y: alias } = obj;"},
+		{"let [first = 1, [second]] = nested;", "let [first = 1, [second]] = nested;"},
+		{"let { pos: { x, y }, ...others } = obj;", "let { pos: { x, y }, ...others } = obj;"},
+		{"let [] = empty;", "let [] = empty;"},
+		{"for [k, v] in pairs { k + v; }", "for [k, v] in pairs { (k + v) }"},
+		{"for item in items { item; }", "for item in items { item }"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(strings.NewReader(tt.input))
+		p := New(l)
+		program := p.ParseProgram()
+		checkParseErrors(t, p)
+
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+
+func TestDestructuringErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [...rest, a] = arr;", "rest element rest must be the last element"},
+		{"let { [a] = b } = obj;", "expected next token to be Ident, got LBracket instead"},
+		{"let [[a] = b] = arr;", "nested pattern [a] cannot have a default value"},
+		{"for 1 in items {}", "expected a name or destructuring pattern, got Number instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(strings.NewReader(tt.input))
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("for input %q, expected first error %q, got %q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestOptionalSemicolons(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"let x = 5\nlet y = x", []string{"let x = 5;", "let y = x;"}},
+		{"a +\nb\nc", []string{"(a + b)", "c"}},
+		{"add(1,\n2)\nreturn", []string{"add(1, 2)", "return ;"}},
+		{"return\nx", []string{"return ;", "x"}},
+		{"function() { return x }", []string{"function() { return x; }"}},
+		{"let f = function() {\n1\n}\nf()", []string{"let f = function() { 1 };", "f()"}},
+		{"for x in xs { x }", []string{"for x in xs { x }"}},
+		{"let x = 1; let y = 2", []string{"let x = 1;", "let y = 2;"}},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(strings.NewReader(tt.input))
+		p := New(l)
+
+		var actual []string
+		for stmt := range p.Statements() {
+			actual = append(actual, stmt.String())
+		}
+		checkParseErrors(t, p)
+
+		if !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("for input %q, expected %q, got %q", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+func TestStatementTerminationErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x = 5 let y = 6", "expected ; or a line break after statement, got Let instead"},
+		{"a b", "expected ; or a line break after statement, got Ident instead"},
+		{"let f = g\n(a + b) * c", "ambiguous line break before LParen on line 2: end the previous line with ; or join the lines"},
+		{"x\n-1", "ambiguous line break before Minus on line 2: end the previous line with ; or join the lines"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(strings.NewReader(tt.input))
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("for input %q, expected first error %q, got %q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"let x 5;", []string{"1:7: error[P0001]: expected next token to be Assign, got Number instead"}},
+		{"1 + #;", []string{"1:5: error[L0002]: invalid character '#'"}},
+		{"let x # 1;", []string{"1:7: error[L0002]: invalid character '#'"}},
+		{"a #", []string{"1:3: error[L0002]: invalid character '#'"}},
+		{"a b", []string{"1:3: error[P0004]: expected ; or a line break after statement, got Ident instead"}},
+		{"function() {\n", []string{"2:1: error[P0009]: expected } to close block, got Eof instead"}},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(strings.NewReader(tt.input))
+		p := New(l)
+		p.ParseProgram()
+
+		var actual []string
+		for _, d := range p.Diagnostics() {
+			actual = append(actual, d.Error())
+		}
+		if !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("for input %q, expected %q, got %q", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+func TestDiagnostics_LabelsAndFixes(t *testing.T) {
+	p := New(lexer.New(strings.NewReader("let f = g\n(a)")))
+	p.ParseProgram()
+
+	diagnostics := p.Diagnostics().WithCode(CodeAmbiguousLineBreak)
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected 1 ambiguous line break, got %v", p.Diagnostics())
+	}
+	d := diagnostics[0]
+
+	if len(d.Labels) != 1 || d.Labels[0].Span.Start.Offset != 8 {
+		t.Errorf("expected a label on g, got %+v", d.Labels)
+	}
+	if len(d.Fixes) != 1 || d.Fixes[0].Replacement != ";" || d.Fixes[0].Span.Start.Offset != 9 {
+		t.Errorf("expected a fix inserting ; after g, got %+v", d.Fixes)
+	}
+}
+
+func TestErrorRecovery(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		statements string
+		errors     []string
+	}{
+		{
+			name:       "Resynchronises at a semicolon",
+			input:      "let x 5 + 6 * 7; let y = 1;",
+			statements: "<error>let y = 1;",
+			errors:     []string{"expected next token to be Assign, got Number instead"},
+		},
+		{
+			name:       "Resynchronises at a line break",
+			input:      "let = 5 6 7\nlet y = 1",
+			statements: "<error>let y = 1;",
+			errors:     []string{"expected next token to be Ident, got Assign instead"},
+		},
+		{
+			name:       "Resynchronises at a statement keyword",
+			input:      "1 + ) ) return 2",
+			statements: "<error>return 2;",
+			errors:     []string{"no prefix parse function for RParen found"},
+		},
+		{
+			name:       "Keeps the rest of a block",
+			input:      "function() { let = 1; a }; b",
+			statements: "function() { <error> a }b",
+			errors:     []string{"expected next token to be Ident, got Assign instead"},
+		},
+		{
+			name:       "Skips a brace group at top level",
+			input:      "fucntion(a) { a }",
+			statements: "<error>",
+			errors:     []string{"expected ; or a line break after statement, got LBrace instead"},
+		},
+		{
+			name:       "Skips a brace group inside a block",
+			input:      "function() { fucntion(a) {\n a\n }\n b }; c",
+			statements: "function() { <error> b }c",
+			errors:     []string{"expected ; or a line break after statement, got LBrace instead"},
+		},
+		{
+			name:       "Reports each broken statement once",
+			input:      "let 1;\nlet 2;\nlet 3;",
+			statements: "<error><error><error>",
+			errors: []string{
+				"expected next token to be Ident, got Number instead",
+				"expected next token to be Ident, got Number instead",
+				"expected next token to be Ident, got Number instead",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(lexer.New(strings.NewReader(tt.input)))
+			program := p.ParseProgram()
+
+			if actual := program.String(); actual != tt.statements {
+				t.Errorf("expected=%q, got=%q", tt.statements, actual)
+			}
+			if !reflect.DeepEqual(p.Errors(), tt.errors) {
+				t.Errorf("expected errors %q, got %q", tt.errors, p.Errors())
+			}
+		})
+	}
+}
+
+func TestErrorRecovery_ErrorNodeSpan(t *testing.T) {
+	p := New(lexer.New(strings.NewReader("x; let x 5 + 6; y")))
+	program := p.ParseProgram()
+
+	if len(program.Statements) != 3 {
+		t.Fatalf("expected 3 statements, got %d", len(program.Statements))
+	}
+	node, ok := program.Statements[1].(*statements.ErrorNode)
+	if !ok {
+		t.Fatalf("expected an ErrorNode, got %T", program.Statements[1])
+	}
+	if node.Span.Start.Offset != 3 || node.Span.End.Offset != 15 {
+		t.Errorf("expected the ErrorNode to span 3-15, got %d-%d", node.Span.Start.Offset, node.Span.End.Offset)
+	}
+}
+
+func TestWithMaxErrors(t *testing.T) {
+	p := New(lexer.New(strings.NewReader("let 1;\nlet 2;\nlet 3;\nlet 4;")), WithMaxErrors(2))
+	p.ParseProgram()
+
+	diagnostics := p.Diagnostics()
+	if len(diagnostics) != 3 {
+		t.Fatalf("expected 2 errors and a final one, got %v", diagnostics)
+	}
+	if last := diagnostics[2]; last.Code != CodeTooManyErrors || last.Message != "too many errors, stopping after 2" {
+		t.Errorf("expected a too many errors diagnostic, got %v", last)
+	}
+
+	// Stopping inside a block does not report the block as unclosed.
+	p = New(lexer.New(strings.NewReader("function() {\n let 1;\n a\n}")), WithMaxErrors(1))
+	p.ParseProgram()
+
+	codes := []string{}
+	for _, d := range p.Diagnostics() {
+		codes = append(codes, d.Code)
+	}
+	if expected := []string{CodeUnexpectedToken, CodeTooManyErrors}; !reflect.DeepEqual(codes, expected) {
+		t.Errorf("expected diagnostics %q, got %q", expected, codes)
+	}
+}
+
+func TestKeywordSuggestions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"retrun x;", `did you mean "return"`},
+		{"let f = fucntion(a) { a };", `did you mean "function"`},
+		{"lte x = 5;", `did you mean "let"`},
+		{"for x ni xs { x }", `did you mean "in"`},
+		{"total x;", ""},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(strings.NewReader(tt.input)))
+		p.ParseProgram()
+
+		diagnostics := p.Diagnostics()
+		if len(diagnostics) == 0 {
+			t.Fatalf("for input %q, expected an error", tt.input)
+		}
+
+		actual := ""
+		if fixes := diagnostics[0].Fixes; len(fixes) > 0 && strings.HasPrefix(fixes[0].Message, "did you mean") {
+			actual = fixes[0].Message
+		}
+		if actual != tt.expected {
+			t.Errorf("for input %q, expected suggestion %q, got %q", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+func TestCustomOperators(t *testing.T) {
+	opts := []Option{
+		WithInfixOperator("in", EQUALS, NonAssociative),
+		WithInfixOperator("matches", EQUALS, NonAssociative),
+		WithInfixOperator("=~", EQUALS, LeftAssociative),
+		WithInfixOperator("->", SUM, RightAssociative),
+		WithPrefixOperator("not", LOGICAL_AND),
+		WithPostfixOperator("!!", CALL),
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x in xs && y;", "((x in xs) && y)"},
+		{"name matches pattern;", "(name matches pattern)"},
+		{"a + 1 =~ b;", "((a + 1) =~ b)"},
+		{"a -> b -> c;", "(a -> (b -> c))"},
+		{"not x in xs;", "(not (x in xs))"},
+		{"not a && b;", "((not a) && b)"},
+		{"n!! * 2;", "((n!!) * 2)"},
+		{"for x in xs { x }", "for x in xs { x }"},
+		{"let matches = 1; matches;", "let matches = 1;matches"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(strings.NewReader(tt.input)), opts...)
+		program := p.ParseProgram()
+		checkParseErrors(t, p)
+
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+
+func TestCustomOperators_PerParser(t *testing.T) {
+	p := New(lexer.New(strings.NewReader("a matches b;")), WithInfixOperator("matches", EQUALS, LeftAssociative))
+	p.ParseProgram()
+	checkParseErrors(t, p)
+
+	p = New(lexer.New(strings.NewReader("a matches b;")))
+	p.ParseProgram()
+	if len(p.Errors()) == 0 {
+		t.Errorf("expected matches to be unknown to a parser without it registered")
+	}
+}
+
+func TestCustomOperators_BuiltinSpellings(t *testing.T) {
+	tests := []struct {
+		input    string
+		option   Option
+		expected string
+	}{
+		{"x!;", WithPostfixOperator("!", CALL), "(x!)"},
+		{"!x!;", WithPostfixOperator("!", CALL), "(!(x!))"},
+		{"+x;", WithPrefixOperator("+", PREFIX), "(+x)"},
+		{"a + +x;", WithPrefixOperator("+", PREFIX), "(a + (+x))"},
+		{"a % b;", WithInfixOperator("%", SUM, LeftAssociative), "(a % b)"},
+		{"a % b * c;", WithInfixOperator("%", SUM, LeftAssociative), "(a % (b * c))"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(strings.NewReader(tt.input)), tt.option)
+		program := p.ParseProgram()
+		checkParseErrors(t, p)
+
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("for input %q, expected=%q, got=%q", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+func TestCustomOperators_NonAssociative(t *testing.T) {
+	p := New(lexer.New(strings.NewReader("a in b in c;")), WithInfixOperator("in", EQUALS, NonAssociative))
+	p.ParseProgram()
+
+	expected := []string{"in cannot be chained with in, add parentheses to group them"}
+	if !reflect.DeepEqual(p.Errors(), expected) {
+		t.Errorf("expected errors %q, got %q", expected, p.Errors())
+	}
+}
+
+func TestAssociativityErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		note     string
+	}{
+		{
+			input:    "a < b < c;",
+			expected: "< cannot be chained with <, add parentheses to group them",
+			note:     "comparisons do not chain as in mathematics; to test both, write a < b && b < ...",
+		},
+		{
+			input:    "a <= b > c;",
+			expected: "<= cannot be chained with >, add parentheses to group them",
+			note:     "comparisons do not chain as in mathematics; to test both, write a <= b && b > ...",
+		},
+		{
+			input:    "a == b != c;",
+			expected: "== cannot be chained with !=, add parentheses to group them",
+			note:     "comparisons do not chain as in mathematics; to test both, write a == b && b != ...",
+		},
+		{
+			input:    "a + b = c;",
+			expected: "cannot assign to (a + b)",
+		},
+		{
+			input:    "1 = 2;",
+			expected: "cannot assign to 1",
+		},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(strings.NewReader(tt.input)))
+		p.ParseProgram()
+
+		diagnostics := p.Diagnostics()
+		if len(diagnostics) != 1 || diagnostics[0].Message != tt.expected {
+			t.Errorf("for input %q, expected error %q, got %v", tt.input, tt.expected, diagnostics)
+			continue
+		}
+
+		var notes []string
+		if tt.note != "" {
+			notes = []string{tt.note}
+		}
+		if !reflect.DeepEqual(diagnostics[0].Notes, notes) {
+			t.Errorf("for input %q, expected notes %q, got %q", tt.input, notes, diagnostics[0].Notes)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"lang/lexer/token"
+	"strconv"
+	"strings"
+)
+
+// WithTrace logs the parser's progress to w: the entry and exit of each parse
+// function, indented by nesting, with the current and peek tokens, and each
+// precedence comparison that decides whether parseExpression continues an
+// expression. It is meant for debugging new operators and surprising trees.
+func WithTrace(w io.Writer) Option {
+	return func(p *Parser) {
+		p.tracer = w
+	}
+}
+
+var precedenceNames = map[int]string{
+	LOWEST:       "LOWEST",
+	ASSIGN:       "ASSIGN",
+	CONDITIONAL:  "CONDITIONAL",
+	NULLISH:      "NULLISH",
+	LOGICAL_OR:   "LOGICAL_OR",
+	LOGICAL_AND:  "LOGICAL_AND",
+	BIT_OR:       "BIT_OR",
+	BIT_XOR:      "BIT_XOR",
+	BIT_AND:      "BIT_AND",
+	EQUALS:       "EQUALS",
+	LESS_GREATER: "LESS_GREATER",
+	SHIFT:        "SHIFT",
+	SUM:          "SUM",
+	PRODUCT:      "PRODUCT",
+	POWER:        "POWER",
+	PREFIX:       "PREFIX",
+	CALL:         "CALL",
+}
+
+// precedenceName returns the name of a precedence level, or its number for
+// levels chosen by embedders between the named ones.
+func precedenceName(precedence int) string {
+	if name, exists := precedenceNames[precedence]; exists {
+		return name
+	}
+	return strconv.Itoa(precedence)
+}
+
+// trace logs entry to the parse function name and returns name for untrace,
+// so that a parse function can begin with
+//
+//	defer p.untrace(p.trace("parseExpression"))
+func (p *Parser) trace(name string) string {
+	if p.tracer == nil {
+		return name
+	}
+	p.tracef("BEGIN %s cur=%s peek=%s", name, traceToken(p.currentToken), traceToken(p.peekToken))
+	p.traceDepth++
+	return name
+}
+
+// untrace logs exit from the parse function name.
+func (p *Parser) untrace(name string) {
+	if p.tracer == nil {
+		return
+	}
+	p.traceDepth--
+	p.tracef("END %s cur=%s", name, traceToken(p.currentToken))
+}
+
+// tracef logs a line at the current depth. Callers check that tracing is
+// enabled first, so that the arguments are not built needlessly.
+func (p *Parser) tracef(format string, args ...any) {
+	fmt.Fprintf(p.tracer, "%s%s\n", strings.Repeat("  ", p.traceDepth), fmt.Sprintf(format, args...))
+}
+
+func traceToken(t token.Token) string {
+	return token.GetStringFromTokenType(t.Type) + " " + strconv.Quote(t.Value)
+}
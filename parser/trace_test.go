@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"lang/lexer"
+	"strings"
+	"testing"
+)
+
+func TestWithTrace(t *testing.T) {
+	var out strings.Builder
+	p := New(lexer.New(strings.NewReader("a - b;")), WithTrace(&out))
+	p.ParseProgram()
+	checkParseErrors(t, p)
+
+	expected := `BEGIN parseStatement cur=Ident "a" peek=Minus "-"
+  BEGIN parseExpressionStatement cur=Ident "a" peek=Minus "-"
+    BEGIN parseExpression cur=Ident "a" peek=Minus "-"
+      precedence LOWEST
+      BEGIN parseIdentifier cur=Ident "a" peek=Minus "-"
+      END parseIdentifier cur=Ident "a"
+      compare peek Minus "-" (SUM) with LOWEST: continue=true
+      BEGIN parseInfixExpression cur=Minus "-" peek=Ident "b"
+        BEGIN parseExpression cur=Ident "b" peek=Semicolon ";"
+          precedence SUM
+          BEGIN parseIdentifier cur=Ident "b" peek=Semicolon ";"
+          END parseIdentifier cur=Ident "b"
+        END parseExpression cur=Ident "b"
+      END parseInfixExpression cur=Ident "b"
+    END parseExpression cur=Ident "b"
+  END parseExpressionStatement cur=Semicolon ";"
+END parseStatement cur=Semicolon ";"
+`
+	if out.String() != expected {
+		t.Errorf("trace =\n%s\nwant\n%s", out.String(), expected)
+	}
+}
+
+func TestWithTrace_Comparisons(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{
+			input: "a * b + c",
+			expected: []string{
+				`compare peek Multiply "*" (PRODUCT) with LOWEST: continue=true`,
+				`compare peek Plus "+" (SUM) with PRODUCT: continue=false`,
+				`compare peek Plus "+" (SUM) with LOWEST: continue=true`,
+				`compare peek Eof "" (LOWEST) with SUM: continue=false`,
+				`compare peek Eof "" (LOWEST) with LOWEST: continue=false`,
+			},
+		},
+		{
+			input: "a ** b ** c",
+			expected: []string{
+				`compare peek Power "**" (POWER) with LOWEST: continue=true`,
+				`compare peek Power "**" (POWER) with PRODUCT: continue=true`,
+				`compare peek Eof "" (LOWEST) with PRODUCT: continue=false`,
+				`compare peek Eof "" (LOWEST) with PRODUCT: continue=false`,
+				`compare peek Eof "" (LOWEST) with LOWEST: continue=false`,
+			},
+		},
+		{
+			input: "a matches b",
+			expected: []string{
+				`compare peek Ident "matches" (18) with LOWEST: continue=true`,
+				`compare peek Eof "" (LOWEST) with 18: continue=false`,
+				`compare peek Eof "" (LOWEST) with LOWEST: continue=false`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		var out strings.Builder
+		p := New(lexer.New(strings.NewReader(tt.input)),
+			WithTrace(&out), WithInfixOperator("matches", CALL+1, LeftAssociative))
+		p.ParseProgram()
+		checkParseErrors(t, p)
+
+		var actual []string
+		for _, line := range strings.Split(out.String(), "\n") {
+			if line = strings.TrimSpace(line); strings.HasPrefix(line, "compare") {
+				actual = append(actual, line)
+			}
+		}
+		if strings.Join(actual, "\n") != strings.Join(tt.expected, "\n") {
+			t.Errorf("for input %q, comparisons =\n%s\nwant\n%s", tt.input, strings.Join(actual, "\n"), strings.Join(tt.expected, "\n"))
+		}
+	}
+}
@@ -4,18 +4,40 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"lang/diag"
 	"lang/lexer"
 	"lang/lexer/token"
+	"lang/parser"
 	"strings"
 )
 
 const Prompt = ">> "
 
+// Option configures the REPL.
+type Option func(*config)
+
+type config struct {
+	color bool
+}
+
+// WithColor enables or disables ANSI colour in rendered diagnostics.
+func WithColor(enabled bool) Option {
+	return func(c *config) {
+		c.color = enabled
+	}
+}
+
 // Start starts the REPL.
 // It reads input from the given reader and writes output to the given writer.
 // Output is in the form of tokens, which are generated whenever the REPL
-// encounters a new line of input from the user
-func Start(in io.Reader, out io.Writer) {
+// encounters a new line of input from the user, followed by any problems
+// found parsing the line, shown against its source.
+func Start(in io.Reader, out io.Writer, opts ...Option) {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+
 	scanner := bufio.NewScanner(in)
 
 	for {
@@ -31,6 +53,8 @@ func Start(in io.Reader, out io.Writer) {
 		for _, tok := range tokens {
 			fmt.Fprintf(out, "%+v\n", tok)
 		}
+
+		_ = diag.NewRenderer(line, diag.WithColor(c.color)).RenderAll(out, check(line))
 	}
 }
 
@@ -38,3 +62,10 @@ func tokenize(line string) []token.Token {
 	l := lexer.New(strings.NewReader(line))
 	return l.Tokenize()
 }
+
+// check parses line and returns the problems found by the lexer and parser.
+func check(line string) diag.List {
+	p := parser.New(lexer.New(strings.NewReader(line)))
+	p.ParseProgram()
+	return p.Diagnostics()
+}